@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Matched",type=integer,JSONPath=`.status.matchedRoutes`
+//+kubebuilder:printcolumn:name="Applied",type=integer,JSONPath=`.status.appliedRoutes`
+//+kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failedRoutes`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterRouteAllowlist is the cluster-scoped counterpart of RouteAllowlist for platform-wide policies.
+// IPSets it references are read from the watch namespace of the operator.
+type ClusterRouteAllowlist struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RouteAllowlistSpec   `json:"spec,omitempty"`
+	Status RouteAllowlistStatus `json:"status,omitempty"`
+}
+
+// GetSpec returns the spec shared with RouteAllowlist
+func (in *ClusterRouteAllowlist) GetSpec() *RouteAllowlistSpec {
+	return &in.Spec
+}
+
+// GetStatus returns the status shared with RouteAllowlist
+func (in *ClusterRouteAllowlist) GetStatus() *RouteAllowlistStatus {
+	return &in.Status
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterRouteAllowlistList contains a list of ClusterRouteAllowlist
+type ClusterRouteAllowlistList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterRouteAllowlist `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterRouteAllowlist{}, &ClusterRouteAllowlistList{})
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// Condition types reported by RouteAllowlists and ClusterRouteAllowlists. Every reconciliation sets all
+// three conditions with the generation of the spec it observed.
+const (
+	// ConditionReady is True once the allowlist is applied to every selected route, and False with the
+	// reason of the failure otherwise.
+	ConditionReady = "Ready"
+	// ConditionProgressing is True while the operator still has work to do, e.g. retrying after a failure.
+	ConditionProgressing = "Progressing"
+	// ConditionDegraded is True with the reason of the failure when the allowlist couldn't be applied.
+	ConditionDegraded = "Degraded"
+	// ConditionDriftDetected is True when the allowlist of a route was modified outside of IPShield. It is
+	// only reported with the Report drift policy.
+	ConditionDriftDetected = "DriftDetected"
+	// ConditionCompliant is reported by IPShieldPolicies. It is True when every selected route carries an
+	// IPShield allowlist, False when some don't and Unknown when the routes couldn't be evaluated.
+	ConditionCompliant = "Compliant"
+)
+
+// Condition reasons
+const (
+	// ReasonApplied is the Ready reason when the allowlist is applied to every selected route
+	ReasonApplied = "Applied"
+	// ReasonNoRoutesMatched is the Ready reason when no route matches the selectors
+	ReasonNoRoutesMatched = "NoRoutesMatched"
+	// ReasonDryRun is the Ready reason in DryRun mode, the changes are reported in the status of the routes
+	ReasonDryRun = "DryRun"
+	// ReasonPaused is the Ready reason in Paused mode
+	ReasonPaused = "Paused"
+	// ReasonAllowlistModified is the DriftDetected reason when the allowlist of a route was modified
+	ReasonAllowlistModified = "AllowlistModified"
+	// ReasonNoDrift is the DriftDetected reason when no allowlist was modified
+	ReasonNoDrift = "NoDrift"
+	// ReasonReconciled is the Progressing reason when nothing is left to do
+	ReasonReconciled = "Reconciled"
+	// ReasonRetrying is the Progressing reason when a failure is retried
+	ReasonRetrying = "Retrying"
+	// ReasonAsExpected is the Degraded reason when nothing failed
+	ReasonAsExpected = "AsExpected"
+
+	// ReasonNoViolations is the Compliant reason when every selected route carries an allowlist
+	ReasonNoViolations = "NoViolations"
+	// ReasonViolationsFound is the Compliant reason when some selected routes don't carry an allowlist
+	ReasonViolationsFound = "ViolationsFound"
+
+	ReasonInvalidSpec                     = "InvalidSpec"
+	ReasonRouteFetchFailed                = "RouteFetchFailed"
+	ReasonNamespaceFetchFailed            = "NamespaceFetchFailed"
+	ReasonIPSetFetchFailed                = "IPSetFetchFailed"
+	ReasonAllowlistFetchFailed            = "AllowlistFetchFailed"
+	ReasonConfigMapUpdateFailed           = "ConfigMapUpdateFailed"
+	ReasonRouteUpdateFailed               = "RouteUpdateFailed"
+	ReasonRouteRestoreFailed              = "RouteRestoreFailed"
+	ReasonAuthorizationPolicyUpdateFailed = "AuthorizationPolicyUpdateFailed"
+)
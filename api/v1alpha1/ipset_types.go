@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IPSetRange is a named IP address or CIDR range
+type IPSetRange struct {
+	// Name identifies the range within the set, e.g. office or vpn
+	Name string `json:"name"`
+	// CIDR is an IPv4 or IPv6 address or CIDR range
+	CIDR string `json:"cidr"`
+	// Description documents why the range is allowed
+	// +optional
+	Description string `json:"description,omitempty"`
+}
+
+// IPSetSpec defines the ranges of an IPSet
+type IPSetSpec struct {
+	// Description documents the purpose of the set
+	// +optional
+	Description string `json:"description,omitempty"`
+	// +listType=map
+	// +listMapKey=name
+	Ranges []IPSetRange `json:"ranges"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:printcolumn:name="Description",type=string,JSONPath=`.spec.description`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// IPSet is a reusable list of IP ranges referenced by RouteAllowlists in the same namespace
+type IPSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IPSetSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// IPSetList contains a list of IPSet
+type IPSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IPSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IPSet{}, &IPSetList{})
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EnforcementAction defines what happens to routes violating an IPShieldPolicy
+// +kubebuilder:validation:Enum=Deny;Audit
+type EnforcementAction string
+
+const (
+	// EnforcementActionDeny rejects violating routes at admission and reports existing violations
+	EnforcementActionDeny EnforcementAction = "Deny"
+	// EnforcementActionAudit only reports violations
+	EnforcementActionAudit EnforcementAction = "Audit"
+)
+
+// IPShieldPolicySpec defines the routes that must be protected by IPShield
+type IPShieldPolicySpec struct {
+	// NamespaceSelector selects the namespaces whose routes must carry an IPShield allowlist
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector"`
+	// RouteSelector restricts the policy to the matching routes, every route of the namespaces if not set
+	// +optional
+	RouteSelector *metav1.LabelSelector `json:"routeSelector,omitempty"`
+	// EnforcementAction is Deny or Audit
+	// +kubebuilder:default=Deny
+	// +optional
+	EnforcementAction EnforcementAction `json:"enforcementAction,omitempty"`
+}
+
+// PolicyViolation is a route that doesn't carry an IPShield allowlist
+type PolicyViolation struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Reason is why the route violates the policy
+	Reason string `json:"reason"`
+}
+
+// IPShieldPolicyStatus defines the observed state of IPShieldPolicy
+type IPShieldPolicyStatus struct {
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ObservedGeneration is the generation of the spec the status was computed from
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Violations are the routes violating the policy, sorted by namespace and name and limited to the first 100
+	// +optional
+	Violations []PolicyViolation `json:"violations,omitempty"`
+	// ViolationCount is the number of routes violating the policy
+	ViolationCount int32 `json:"violationCount"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Action",type=string,JSONPath=`.spec.enforcementAction`
+//+kubebuilder:printcolumn:name="Compliant",type=string,JSONPath=`.status.conditions[?(@.type=="Compliant")].status`
+//+kubebuilder:printcolumn:name="Violations",type=integer,JSONPath=`.status.violationCount`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// IPShieldPolicy requires every route of the selected namespaces to carry an IPShield allowlist, that is to be
+// labelled with ipshield.stakater.cloud/enabled=true and selected by a RouteAllowlist or ClusterRouteAllowlist
+// applying a non-empty allowlist.
+type IPShieldPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IPShieldPolicySpec   `json:"spec,omitempty"`
+	Status IPShieldPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// IPShieldPolicyList contains a list of IPShieldPolicy
+type IPShieldPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IPShieldPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IPShieldPolicy{}, &IPShieldPolicyList{})
+}
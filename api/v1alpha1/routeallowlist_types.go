@@ -23,22 +23,240 @@ import (
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// Mode defines whether the allowlist is applied to the routes
+// +kubebuilder:validation:Enum=Enforce;DryRun;Paused
+type Mode string
+
+const (
+	// ModeEnforce applies the allowlist to the routes
+	ModeEnforce Mode = "Enforce"
+	// ModeDryRun reports the changes the allowlist would make to the routes without applying them
+	ModeDryRun Mode = "DryRun"
+	// ModePaused leaves the routes untouched and only refreshes the status
+	ModePaused Mode = "Paused"
+)
+
+// Strategy defines how the ranges of the allowlist are combined with the original allowlist of a route
+// +kubebuilder:validation:Enum=Merge;Replace
+type Strategy string
+
+const (
+	// StrategyMerge adds the ranges to the original allowlist of the route, backed up when it was first selected
+	StrategyMerge Strategy = "Merge"
+	// StrategyReplace makes the allowlist of the route exactly the union of the ranges of every allowlist selecting it,
+	// dropping the original allowlist
+	StrategyReplace Strategy = "Replace"
+)
+
+// DriftPolicy defines how changes made to the allowlist of a route outside of IPShield are handled
+// +kubebuilder:validation:Enum=Enforce;Report;Ignore
+type DriftPolicy string
+
+const (
+	// DriftPolicyEnforce reverts the changes
+	DriftPolicyEnforce DriftPolicy = "Enforce"
+	// DriftPolicyReport keeps the changes and reports them in the status and as Events on the route
+	DriftPolicyReport DriftPolicy = "Report"
+	// DriftPolicyIgnore keeps the changes
+	DriftPolicyIgnore DriftPolicy = "Ignore"
+)
+
+// TargetKind is a kind of object the allowlist is applied to. HTTPRoutes are only supported when Gateway API and
+// Envoy Gateway are installed, IngressRoutes when Traefik is, other kinds are enforced by backends added to the operator.
+// +kubebuilder:validation:MinLength=1
+type TargetKind string
+
+const (
+	// TargetRoute selects OpenShift Routes, the allowlist is written to the annotation of the HAProxy router
+	TargetRoute TargetKind = "Route"
+	// TargetIngress selects Kubernetes Ingresses, the allowlist is written to the annotation of ingress-nginx
+	TargetIngress TargetKind = "Ingress"
+	// TargetHTTPRoute selects Gateway API HTTPRoutes, the allowlist is enforced by an Envoy Gateway SecurityPolicy
+	TargetHTTPRoute TargetKind = "HTTPRoute"
+	// TargetIngressRoute selects Traefik IngressRoutes, the allowlist is enforced by an ipAllowList Middleware
+	TargetIngressRoute TargetKind = "IngressRoute"
+)
+
+// IPSource defines the address of the client matched against the ranges by the Istio AuthorizationPolicies
+// +kubebuilder:validation:Enum=RemoteIP;PeerIP
+type IPSource string
+
+const (
+	// IPSourceRemote matches the original client address forwarded by the ingress gateway, with remoteIpBlocks
+	IPSourceRemote IPSource = "RemoteIP"
+	// IPSourcePeer matches the address of the peer connection, with ipBlocks
+	IPSourcePeer IPSource = "PeerIP"
+)
+
+// AuthorizationPolicySpec defines the Istio AuthorizationPolicies generated for the workloads behind the selected routes
+type AuthorizationPolicySpec struct {
+	// IPSource is RemoteIP or PeerIP. RemoteIP requires the ingress gateway to be configured with the number of
+	// trusted proxies in front of it.
+	// +kubebuilder:default=RemoteIP
+	// +optional
+	IPSource IPSource `json:"ipSource,omitempty"`
+}
+
 // RouteAllowlistSpec defines the desired state of RouteAllowlist
 type RouteAllowlistSpec struct {
+	// Mode is Enforce, DryRun or Paused. Routes are restored on deletion whatever the mode.
+	// +kubebuilder:default=Enforce
+	// +optional
+	Mode Mode `json:"mode,omitempty"`
+
+	// Strategy is Merge or Replace. Merge keeps the original allowlist of the route next to the ranges of the
+	// allowlists. With Replace the original allowlist is dropped, it is still backed up and restored once no allowlist
+	// selects the route. Ranges edited by hand afterwards are handled following DriftPolicy with both strategies.
+	// +kubebuilder:default=Merge
+	// +optional
+	Strategy Strategy `json:"strategy,omitempty"`
+
+	// DriftPolicy is Enforce, Report or Ignore. Any change to the allowlist of a route made after IPShield manages it
+	// is drift, whatever the strategy: the expected allowlist is the original allowlist, backed up when the route
+	// was first selected, and the ranges of the allowlists.
+	// +kubebuilder:default=Enforce
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// Targets are the kinds of objects selected by LabelSelector, Routes if unset. Objects of a kind removed
+	// from Targets are restored. The allowlist isn't applied if a kind has no backend.
+	// +kubebuilder:default={Route}
+	// +listType=set
+	// +optional
+	Targets []TargetKind `json:"targets,omitempty"`
+
+	// AuthorizationPolicy renders the ranges into an Istio AuthorizationPolicy allowing only them, for the workloads
+	// behind the service of each selected route, so traffic bypassing the router is filtered too. Policies are
+	// labelled with the UID of the allowlist and deleted when unset, when the route is no longer selected or when the
+	// allowlist is deleted. They are only owned by the allowlist when in the same namespace.
+	// +optional
+	AuthorizationPolicy *AuthorizationPolicySpec `json:"authorizationPolicy,omitempty"`
+
 	LabelSelector *metav1.LabelSelector `json:"labelSelector"`
-	IPRanges      []string              `json:"ipRanges"`
+	// NamespaceSelector restricts the selected routes to the namespaces matching the selector
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Namespaces restricts the selected routes to the listed namespaces. Routes of every namespace
+	// are selected when neither Namespaces nor NamespaceSelector is set, and routes must match both when both are.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// +optional
+	IPRanges []string `json:"ipRanges,omitempty"`
+	// IPSetRefs are the names of IPSets in the same namespace whose ranges are added to IPRanges
+	// +optional
+	IPSetRefs []string `json:"ipSetRefs,omitempty"`
+	// Entries are documented ranges, optionally only allowed within a time window, added to IPRanges while active
+	// +optional
+	Entries []IPRangeEntry `json:"entries,omitempty"`
+}
+
+// IPRangeEntry is an IP address or CIDR range with the metadata explaining why it is allowed
+type IPRangeEntry struct {
+	// CIDR is an IPv4 or IPv6 address or CIDR range
+	CIDR string `json:"cidr"`
+	// Description explains why the range is allowed
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Owner is the person or team responsible for the range
+	// +optional
+	Owner string `json:"owner,omitempty"`
+	// Reference links the range to a ticket or change request
+	// +optional
+	Reference string `json:"reference,omitempty"`
+	// NotBefore is the time the range is allowed from, the range is allowed immediately if unset
+	// +optional
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// ExpiresAt is the time the range is retracted from the routes, the range never expires if unset
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// IPRangeEntryState is the state of an IPRangeEntry
+// +kubebuilder:validation:Enum=Pending;Active;Expired
+type IPRangeEntryState string
+
+const (
+	// IPRangeEntryPending entries are not allowed yet
+	IPRangeEntryPending IPRangeEntryState = "Pending"
+	// IPRangeEntryActive entries are applied to the routes
+	IPRangeEntryActive IPRangeEntryState = "Active"
+	// IPRangeEntryExpired entries have been retracted from the routes
+	IPRangeEntryExpired IPRangeEntryState = "Expired"
+)
+
+// IPRangeEntryStatus reports the state of an IPRangeEntry
+type IPRangeEntryStatus struct {
+	IPRangeEntry `json:",inline"`
+	// State is Pending before NotBefore, Expired after ExpiresAt and Active in between
+	State IPRangeEntryState `json:"state"`
 }
 
 // RouteAllowlistStatus defines the observed state of RouteAllowlist
-// TODO add conditions
 type RouteAllowlistStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the status was computed from
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Routes lists the routes and ingresses the allowlist is currently applied to.
+	// Objects that are no longer selected are restored on the next reconciliation.
+	Routes []RouteStatus `json:"routes,omitempty"`
+
+	// MatchedRoutes is the number of routes matching the selectors
+	MatchedRoutes int32 `json:"matchedRoutes,omitempty"`
+	// AppliedRoutes is the number of routes the allowlist was applied to
+	AppliedRoutes int32 `json:"appliedRoutes,omitempty"`
+	// FailedRoutes is the number of routes the allowlist failed to be applied to
+	FailedRoutes int32 `json:"failedRoutes,omitempty"`
+
+	// Entries lists the entries of the spec with their metadata and state
+	Entries []IPRangeEntryStatus `json:"entries,omitempty"`
+}
+
+// RouteStatus reports the allowlist applied to a route or an ingress
+type RouteStatus struct {
+	// Kind is the kind of the target, targets reported without a kind are routes
+	// +optional
+	Kind      TargetKind `json:"kind,omitempty"`
+	Namespace string     `json:"namespace"`
+	Name      string     `json:"name"`
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Allowlist is the effective allowlist of the route, ranges separated by spaces
+	// +optional
+	Allowlist string `json:"allowlist,omitempty"`
+	// LastAppliedTime is the last time the allowlist annotation of the route was updated
+	// +optional
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+	// Error is the reason the allowlist couldn't be applied to the route
+	// +optional
+	Error string `json:"error,omitempty"`
+	// PendingChanges are the changes the allowlist would make to the route, only reported in DryRun mode
+	// +optional
+	PendingChanges *AllowlistChanges `json:"pendingChanges,omitempty"`
+	// Drift are the changes made to the allowlist of the route outside of IPShield, only reported with the Report drift policy
+	// +optional
+	Drift *AllowlistChanges `json:"drift,omitempty"`
+}
+
+// AllowlistChanges are the ranges added to and removed from the allowlist annotation of a route
+type AllowlistChanges struct {
+	// +optional
+	Added []string `json:"added,omitempty"`
+	// +optional
+	Removed []string `json:"removed,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Matched",type=integer,JSONPath=`.status.matchedRoutes`
+//+kubebuilder:printcolumn:name="Applied",type=integer,JSONPath=`.status.appliedRoutes`
+//+kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failedRoutes`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // RouteAllowlist is the Schema for the RouteAllowlists API
 type RouteAllowlist struct {
@@ -49,6 +267,16 @@ type RouteAllowlist struct {
 	Status RouteAllowlistStatus `json:"status,omitempty"`
 }
 
+// GetSpec returns the spec shared with ClusterRouteAllowlist
+func (in *RouteAllowlist) GetSpec() *RouteAllowlistSpec {
+	return &in.Spec
+}
+
+// GetStatus returns the status shared with ClusterRouteAllowlist
+func (in *RouteAllowlist) GetStatus() *RouteAllowlistStatus {
+	return &in.Status
+}
+
 //+kubebuilder:object:root=true
 
 // RouteAllowlistList contains a list of RouteAllowlist
@@ -17,9 +17,13 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
@@ -37,9 +41,13 @@ import (
 
 	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
 	"github.com/stakater/ipshield-operator/internal/controller"
+	webhookroutev1 "github.com/stakater/ipshield-operator/internal/webhook/v1"
+	webhooknetworkingv1alpha1 "github.com/stakater/ipshield-operator/internal/webhook/v1alpha1"
+	"github.com/stakater/ipshield-operator/pkg/backend"
 
 	//+kubebuilder:scaffold:imports
 
+	configv1 "github.com/openshift/api/config/v1"
 	route "github.com/openshift/api/route/v1"
 )
 
@@ -51,6 +59,7 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(route.AddToScheme(scheme))
+	utilruntime.Must(configv1.AddToScheme(scheme))
 	utilruntime.Must(networkingv1alpha1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
@@ -61,6 +70,7 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var resyncPeriod time.Duration
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -70,6 +80,8 @@ func main() {
 		"If set the metrics endpoint is served securely")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&resyncPeriod, "resync-period", 10*time.Minute,
+		"The interval at which allowlists are reconciled again to catch changes made to routes by hand. 0 disables it.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -132,6 +144,11 @@ func main() {
 						watchNamespace: {},
 					},
 				},
+				&networkingv1alpha1.IPSet{}: {
+					Namespaces: map[string]cache.Config{
+						watchNamespace: {},
+					},
+				},
 			}
 			return cache.New(config, opts)
 		},
@@ -141,14 +158,87 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&controller.RouteAllowlistReconciler{
-		Client:         mgr.GetClient(),
-		Scheme:         mgr.GetScheme(),
-		WatchNamespace: watchNamespace,
-	}).SetupWithManager(mgr); err != nil {
+	// The cache is not started yet, so the cluster version is read directly from the API server
+	allowlistAnnotation, err := controller.GetAllowlistAnnotation(context.Background(), mgr.GetAPIReader())
+	if err != nil {
+		setupLog.Error(err, "unable to determine allowlist annotation")
+		os.Exit(1)
+	}
+	setupLog.Info("writing allowlists to route annotation", "annotation", allowlistAnnotation)
+
+	// Backends of APIs that aren't installed, e.g. HTTPRoutes without Envoy Gateway, are left out. Backends of
+	// other modules are registered through backend.Register by importing them in this package
+	backends := append(backend.Defaults(allowlistAnnotation), &backend.HTTPRoute{}, &backend.Traefik{})
+	backends = backend.Available(mgr.GetRESTMapper(), append(backends, backend.Registered()...)...)
+	for _, b := range backends {
+		setupLog.Info("enforcing allowlists", "target", b.Kind())
+	}
+
+	// AuthorizationPolicies can only be generated once Istio is installed
+	_, err = mgr.GetRESTMapper().RESTMapping(controller.AuthorizationPolicyGVK.GroupKind(), controller.AuthorizationPolicyGVK.Version)
+	authorizationPolicies := err == nil
+	setupLog.Info("generating Istio AuthorizationPolicies", "enabled", authorizationPolicies)
+
+	routeAllowlistReconciler := &controller.RouteAllowlistReconciler{
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		WatchNamespace:        watchNamespace,
+		RouteAnnotation:       allowlistAnnotation,
+		Backends:              backends,
+		Recorder:              mgr.GetEventRecorderFor("ipshield-operator"),
+		ResyncPeriod:          resyncPeriod,
+		AuthorizationPolicies: authorizationPolicies,
+	}
+	if err = routeAllowlistReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RouteAllowlist")
 		os.Exit(1)
 	}
+	if err = (&controller.ClusterRouteAllowlistReconciler{
+		RouteAllowlistReconciler: controller.RouteAllowlistReconciler{
+			Client:                mgr.GetClient(),
+			Scheme:                mgr.GetScheme(),
+			WatchNamespace:        watchNamespace,
+			RouteAnnotation:       allowlistAnnotation,
+			Backends:              backends,
+			Recorder:              mgr.GetEventRecorderFor("ipshield-operator"),
+			ResyncPeriod:          resyncPeriod,
+			AuthorizationPolicies: authorizationPolicies,
+		},
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterRouteAllowlist")
+		os.Exit(1)
+	}
+	policyReconciler := &controller.IPShieldPolicyReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+	if err = policyReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "IPShieldPolicy")
+		os.Exit(1)
+	}
+	// Webhooks can be disabled when running the manager locally without certificates
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhooknetworkingv1alpha1.SetupRouteAllowlistWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RouteAllowlist")
+			os.Exit(1)
+		}
+		if err = webhooknetworkingv1alpha1.SetupClusterRouteAllowlistWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterRouteAllowlist")
+			os.Exit(1)
+		}
+		if err = webhooknetworkingv1alpha1.SetupIPSetWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "IPSet")
+			os.Exit(1)
+		}
+		routeValidator := getRouteValidator()
+		routeValidator.Policies = policyReconciler
+		routeValidator.Allowlists = routeAllowlistReconciler
+		if err = webhookroutev1.SetupRouteWebhookWithManager(mgr,
+			&webhookroutev1.RouteCustomDefaulter{Reconciler: routeAllowlistReconciler}, routeValidator); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Route")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -166,3 +256,25 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// getRouteValidator configures the protection of routes against tampering from the environment. The service
+// account of the operator, read from POD_NAMESPACE and POD_SERVICE_ACCOUNT, may always remove it.
+func getRouteValidator() *webhookroutev1.RouteCustomValidator {
+	validator := &webhookroutev1.RouteCustomValidator{
+		Enabled: os.Getenv("ENABLE_ROUTE_PROTECTION") == "true",
+	}
+
+	for _, group := range strings.Split(os.Getenv("ROUTE_PROTECTION_BYPASS_GROUPS"), ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			validator.BypassGroups = append(validator.BypassGroups, group)
+		}
+	}
+
+	namespace, serviceAccount := os.Getenv("POD_NAMESPACE"), os.Getenv("POD_SERVICE_ACCOUNT")
+	if namespace != "" && serviceAccount != "" {
+		validator.BypassUsers = append(validator.BypassUsers, fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount))
+	} else if validator.Enabled {
+		setupLog.Info("POD_NAMESPACE or POD_SERVICE_ACCOUNT not set, the operator may be denied updates of protected routes")
+	}
+	return validator
+}
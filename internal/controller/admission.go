@@ -0,0 +1,211 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	route "github.com/openshift/api/route/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+	"github.com/stakater/ipshield-operator/pkg/backend"
+	"github.com/stakater/ipshield-operator/pkg/cidr"
+)
+
+// OriginalAllowlistAnnotation holds the allowlist of a route before ApplyAllowlists updated it, until the
+// reconcilers back it up in the config map
+const OriginalAllowlistAnnotation = "ipshield.stakater.cloud/original-allowlist"
+
+// ApplyAllowlists writes to a route that isn't managed yet the allowlist and contributions of every enforced
+// RouteAllowlist and ClusterRouteAllowlist selecting it, as the reconcilers would. It reports whether the route
+// was updated.
+func (r *RouteAllowlistReconciler) ApplyAllowlists(ctx context.Context, watchedRoute *route.Route) (bool, error) {
+	if val, ok := watchedRoute.Labels[IPShieldWatchedResourceLabel]; !ok || val != "true" {
+		return false, nil
+	}
+	if _, ok := watchedRoute.Annotations[ContributionsAnnotation]; ok {
+		// The reconcilers are the source of truth once the route is managed
+		return false, nil
+	}
+
+	allowlists, err := r.getAllowlists(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var next contributions
+	now := r.now()
+	for _, cr := range allowlists {
+		selected, err := r.selectsRoute(ctx, cr, watchedRoute)
+		if err != nil {
+			return false, err
+		}
+		if !selected {
+			continue
+		}
+
+		ipRanges, err := r.getIPRanges(ctx, cr, now)
+		if err != nil {
+			return false, err
+		}
+		next = next.with(getOwnerKey(cr), ipRanges, cr.GetSpec().Strategy)
+	}
+
+	if len(next) == 0 {
+		return false, nil
+	}
+
+	if watchedRoute.Annotations == nil {
+		watchedRoute.Annotations = make(map[string]string)
+	}
+
+	current := backend.GetRouteAllowlist(watchedRoute.Annotations)
+	original := diffSet(current, nil)
+	backend.SetRouteAllowlist(watchedRoute.Annotations, r.getRouteAnnotation(), strings.Fields(computeAllowlist(original, next)))
+	if err = setContributions(watchedRoute.Annotations, next); err != nil {
+		return false, err
+	}
+	watchedRoute.Annotations[OriginalAllowlistAnnotation] = original
+
+	return true, nil
+}
+
+// getAllowlists returns the enforced RouteAllowlists of the watch namespace and ClusterRouteAllowlists
+func (r *RouteAllowlistReconciler) getAllowlists(ctx context.Context) ([]allowlistObject, error) {
+	allowlists := &networkingv1alpha1.RouteAllowlistList{}
+	if err := r.List(ctx, allowlists, client.InNamespace(r.WatchNamespace)); err != nil {
+		return nil, err
+	}
+
+	clusterAllowlists := &networkingv1alpha1.ClusterRouteAllowlistList{}
+	if err := r.List(ctx, clusterAllowlists); err != nil {
+		return nil, err
+	}
+
+	var result []allowlistObject
+	for i := range allowlists.Items {
+		if isEnforced(&allowlists.Items[i]) {
+			result = append(result, &allowlists.Items[i])
+		}
+	}
+	for i := range clusterAllowlists.Items {
+		if isEnforced(&clusterAllowlists.Items[i]) {
+			result = append(result, &clusterAllowlists.Items[i])
+		}
+	}
+	return result, nil
+}
+
+func isEnforced(cr allowlistObject) bool {
+	mode := cr.GetSpec().Mode
+	return cr.GetDeletionTimestamp() == nil && (mode == "" || mode == networkingv1alpha1.ModeEnforce)
+}
+
+// selectsRoute reports whether the CR targets routes and the route matches its label selector and namespaces
+func (r *RouteAllowlistReconciler) selectsRoute(ctx context.Context, cr allowlistObject, watchedRoute *route.Route) (bool, error) {
+	if !isTargeted(cr, networkingv1alpha1.TargetRoute) {
+		return false, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(cr.GetSpec().LabelSelector)
+	if err != nil {
+		return false, err
+	}
+	if !selector.Matches(labels.Set(watchedRoute.Labels)) {
+		return false, nil
+	}
+
+	namespaces, err := r.getSelectedNamespaces(ctx, cr)
+	if err != nil {
+		return false, err
+	}
+	return namespaces == nil || namespaces.Contains(watchedRoute.Namespace), nil
+}
+
+// ValidateRouteUpdate returns an error naming the responsible allowlists when an update removes the protection
+// IPShield applied to a route, that is the enabled label or ranges contributed by an allowlist
+func ValidateRouteUpdate(oldRoute, newRoute *route.Route) error {
+	previous, err := getContributions(oldRoute.Annotations)
+	if err != nil || len(previous) == 0 {
+		// The route isn't protected by IPShield
+		return nil
+	}
+
+	if val, ok := newRoute.Labels[IPShieldWatchedResourceLabel]; !ok || val != "true" {
+		return fmt.Errorf("label %s can't be removed, the route is protected by %s", IPShieldWatchedResourceLabel, describeOwners(previous))
+	}
+
+	allowlist := backend.GetRouteAllowlist(newRoute.Annotations)
+	var removed []string
+	var owners contributions
+	for _, item := range previous {
+		if ranges := cidr.Subtract(item.Ranges, allowlist); len(ranges) > 0 {
+			removed = append(removed, ranges...)
+			owners = append(owners, item)
+		}
+	}
+	if len(removed) > 0 {
+		return fmt.Errorf("ranges %s can't be removed from the allowlist, they are required by %s",
+			mergeSet(removed, nil), describeOwners(owners))
+	}
+	return nil
+}
+
+// ValidateContributions reports whether the contributions of a route are the ones maintained by the operator.
+// Contributions of a protected route can't be changed, and those of other routes must be the ones ApplyAllowlists
+// writes upon admission. oldRoute is nil when the route is created.
+func (r *RouteAllowlistReconciler) ValidateContributions(ctx context.Context, oldRoute, newRoute *route.Route) (bool, error) {
+	value, ok := newRoute.Annotations[ContributionsAnnotation]
+	if oldRoute != nil {
+		if previous, managed := oldRoute.Annotations[ContributionsAnnotation]; managed {
+			return ok && previous == value, nil
+		}
+	}
+	if !ok {
+		return true, nil
+	}
+
+	// The allowlist the route was admitted with is recorded next to the contributions
+	expected := newRoute.DeepCopy()
+	delete(expected.Annotations, ContributionsAnnotation)
+	if original, ok := expected.Annotations[OriginalAllowlistAnnotation]; ok {
+		backend.SetRouteAllowlist(expected.Annotations, r.getRouteAnnotation(), strings.Fields(original))
+		delete(expected.Annotations, OriginalAllowlistAnnotation)
+	}
+	if _, err := r.ApplyAllowlists(ctx, expected); err != nil {
+		return false, err
+	}
+	return expected.Annotations[ContributionsAnnotation] == value, nil
+}
+
+// describeOwners names the kind and key of the allowlists of the contributions
+func describeOwners(c contributions) string {
+	var result []string
+	for _, item := range c {
+		if strings.Contains(item.Owner, "/") {
+			result = append(result, "RouteAllowlist "+item.Owner)
+		} else {
+			result = append(result, "ClusterRouteAllowlist "+item.Owner)
+		}
+	}
+	return strings.Join(result, ", ")
+}
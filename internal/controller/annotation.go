@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// AutoDetectAllowlistAnnotation selects the allowlist annotation from the cluster version
+	AutoDetectAllowlistAnnotation = "auto"
+
+	clusterVersionName = "version"
+)
+
+// ipAllowlistMinVersion is the first OpenShift release whose router understands IPAllowlistAnnotation
+var ipAllowlistMinVersion = version.MustParseGeneric("4.14.0")
+
+//+kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get
+
+// GetAllowlistAnnotation returns the route annotation allowlists are written to. It is read from the
+// ALLOWLIST_ANNOTATION environment variable, or detected from the OpenShift cluster version when the
+// variable is unset or set to "auto".
+func GetAllowlistAnnotation(ctx context.Context, reader client.Reader) (string, error) {
+	switch annotation := getEnv("ALLOWLIST_ANNOTATION", AutoDetectAllowlistAnnotation); annotation {
+	case AllowlistAnnotation, IPAllowlistAnnotation:
+		return annotation, nil
+	case AutoDetectAllowlistAnnotation, "":
+		return detectAllowlistAnnotation(ctx, reader), nil
+	default:
+		return "", fmt.Errorf("unsupported allowlist annotation %q, expected %s, %s or %s",
+			annotation, AllowlistAnnotation, IPAllowlistAnnotation, AutoDetectAllowlistAnnotation)
+	}
+}
+
+// detectAllowlistAnnotation falls back to the legacy annotation, which every router release understands,
+// when the completed cluster version can't be determined
+func detectAllowlistAnnotation(ctx context.Context, reader client.Reader) string {
+	logger := log.FromContext(ctx).WithName("detectAllowlistAnnotation")
+
+	clusterVersion := &configv1.ClusterVersion{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: clusterVersionName}, clusterVersion); err != nil {
+		logger.Error(err, "failed to get cluster version, using legacy allowlist annotation")
+		return AllowlistAnnotation
+	}
+
+	// Routers only understand the new annotation once the upgrade to a release supporting it has completed,
+	// history lists the most recent update first
+	completed := ""
+	for _, update := range clusterVersion.Status.History {
+		if update.State == configv1.CompletedUpdate {
+			completed = update.Version
+			break
+		}
+	}
+
+	current, err := version.ParseGeneric(completed)
+	if err != nil {
+		logger.Error(err, "failed to parse cluster version, using legacy allowlist annotation")
+		return AllowlistAnnotation
+	}
+
+	if current.AtLeast(ipAllowlistMinVersion) {
+		return IPAllowlistAnnotation
+	}
+	return AllowlistAnnotation
+}
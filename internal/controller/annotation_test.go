@@ -0,0 +1,87 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Allowlist annotation detection", func() {
+
+	getClusterVersion := func(version string, history ...configv1.UpdateHistory) *configv1.ClusterVersion {
+		if len(history) == 0 {
+			history = []configv1.UpdateHistory{{State: configv1.CompletedUpdate, Version: version}}
+		}
+		return &configv1.ClusterVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterVersionName},
+			Status: configv1.ClusterVersionStatus{
+				Desired: configv1.Release{Version: version},
+				History: history,
+			},
+		}
+	}
+
+	DescribeTable("detects the annotation from the cluster version",
+		func(version, expected string) {
+			fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme.Scheme).
+				WithObjects(getClusterVersion(version)).
+				Build()
+
+			Expect(GetAllowlistAnnotation(context.Background(), fakeClient)).To(Equal(expected))
+		},
+		Entry("older release", "4.13.12", AllowlistAnnotation),
+		Entry("first release with the new annotation", "4.14.0", IPAllowlistAnnotation),
+		Entry("newer release", "4.16.3", IPAllowlistAnnotation),
+		Entry("unknown version", "", AllowlistAnnotation),
+	)
+
+	It("keeps the legacy annotation until the upgrade to a release with the new annotation completes", func() {
+		fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(getClusterVersion("4.14.1",
+				configv1.UpdateHistory{State: configv1.PartialUpdate, Version: "4.14.1"},
+				configv1.UpdateHistory{State: configv1.CompletedUpdate, Version: "4.13.12"},
+			)).
+			Build()
+
+		Expect(GetAllowlistAnnotation(context.Background(), fakeClient)).To(Equal(AllowlistAnnotation))
+	})
+
+	It("falls back to the legacy annotation when the cluster version is missing", func() {
+		fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+		Expect(GetAllowlistAnnotation(context.Background(), fakeClient)).To(Equal(AllowlistAnnotation))
+	})
+
+	It("uses the configured annotation", func() {
+		GinkgoT().Setenv("ALLOWLIST_ANNOTATION", AllowlistAnnotation)
+		fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(getClusterVersion("4.16.3")).
+			Build()
+
+		Expect(GetAllowlistAnnotation(context.Background(), fakeClient)).To(Equal(AllowlistAnnotation))
+
+		GinkgoT().Setenv("ALLOWLIST_ANNOTATION", "haproxy.router.openshift.io/unknown")
+		Expect(GetAllowlistAnnotation(context.Background(), fakeClient)).Error().To(HaveOccurred())
+	})
+})
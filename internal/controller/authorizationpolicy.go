@@ -0,0 +1,198 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	set "github.com/deckarep/golang-set/v2"
+	"github.com/go-logr/logr"
+	route "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+	"github.com/stakater/ipshield-operator/pkg/backend"
+)
+
+const (
+	// AuthorizationPolicyOwnerLabel holds the UID of the allowlist an AuthorizationPolicy was generated for
+	AuthorizationPolicyOwnerLabel = "ipshield.stakater.cloud/owner-uid"
+
+	authorizationPolicyPrefix = "ipshield-"
+)
+
+// AuthorizationPolicyGVK is the kind of the Istio policies generated for the workloads behind the routes
+var AuthorizationPolicyGVK = schema.GroupVersionKind{Group: "security.istio.io", Version: "v1", Kind: "AuthorizationPolicy"}
+
+// getSelectedRoutes returns the OpenShift Routes selected by the CR the allowlist is applied to, that is the ones
+// with the enabled label
+func getSelectedRoutes(selections []selection) []*route.Route {
+	var result []*route.Route
+	for _, s := range selections {
+		if s.backend.Kind() != networkingv1alpha1.TargetRoute {
+			continue
+		}
+		for _, obj := range s.selected {
+			if val, ok := obj.GetLabels()[IPShieldWatchedResourceLabel]; !ok || val != "true" {
+				continue
+			}
+			if watchedRoute, ok := obj.(*route.Route); ok {
+				result = append(result, watchedRoute)
+			}
+		}
+	}
+	return result
+}
+
+// getAuthorizationPolicyName returns the name of the policy of the CR for the route. The CR and route are hashed, so
+// the names of RouteAllowlists and ClusterRouteAllowlists don't collide and stay within the length limit.
+func getAuthorizationPolicyName(cr allowlistObject, watchedRoute *route.Route) string {
+	kind := "routeallowlist"
+	if cr.GetNamespace() == "" {
+		kind = "clusterrouteallowlist"
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{kind, getOwnerKey(cr), watchedRoute.Name}, "/")))
+	return authorizationPolicyPrefix + kind + "-" + hex.EncodeToString(sum[:8])
+}
+
+// syncAuthorizationPolicies generates the AuthorizationPolicy of the CR for the workloads behind each route, and
+// deletes the policies of the CR generated for other routes. Every policy of the CR is deleted when it doesn't
+// request them, or when routes is empty.
+func (r *RouteAllowlistReconciler) syncAuthorizationPolicies(ctx context.Context, cr allowlistObject, routes []*route.Route,
+	ipRanges []string, logger logr.Logger) error {
+	if !r.AuthorizationPolicies {
+		return nil
+	}
+
+	generated := set.NewSet[types.NamespacedName]()
+	// Like the backends, no policy is enforced for an empty allowlist
+	if spec := cr.GetSpec().AuthorizationPolicy; spec != nil && len(ipRanges) > 0 {
+		for _, watchedRoute := range routes {
+			key, err := r.applyAuthorizationPolicy(ctx, cr, spec, watchedRoute, ipRanges, logger)
+			if err != nil {
+				return err
+			}
+			if key != nil {
+				generated.Add(*key)
+			}
+		}
+	}
+
+	policies := &unstructured.UnstructuredList{}
+	policies.SetGroupVersionKind(AuthorizationPolicyGVK.GroupVersion().WithKind(AuthorizationPolicyGVK.Kind + "List"))
+	if err := r.List(ctx, policies, client.MatchingLabels{AuthorizationPolicyOwnerLabel: string(cr.GetUID())}); err != nil {
+		return err
+	}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if generated.Contains(client.ObjectKeyFromObject(policy)) {
+			continue
+		}
+		if err := r.Delete(ctx, policy); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		logger.Info("AuthorizationPolicy deleted", "namespace", policy.GetNamespace(), "name", policy.GetName())
+	}
+	return nil
+}
+
+// applyAuthorizationPolicy creates or updates the policy of the CR allowing the ranges for the pods selected by the
+// service of the route. No policy is generated for routes to other kinds of backends or to services without selector.
+func (r *RouteAllowlistReconciler) applyAuthorizationPolicy(ctx context.Context, cr allowlistObject,
+	spec *networkingv1alpha1.AuthorizationPolicySpec, watchedRoute *route.Route, ipRanges []string, logger logr.Logger) (*types.NamespacedName, error) {
+	if watchedRoute.Spec.To.Kind != "" && watchedRoute.Spec.To.Kind != "Service" {
+		return nil, nil
+	}
+
+	service := &corev1.Service{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: watchedRoute.Namespace, Name: watchedRoute.Spec.To.Name}, service)
+	if err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if len(service.Spec.Selector) == 0 {
+		logger.Info("AuthorizationPolicy skipped, the service of the route has no selector", "route", getRouteFullName(watchedRoute))
+		return nil, nil
+	}
+
+	matchLabels := make(map[string]interface{}, len(service.Spec.Selector))
+	for key, value := range service.Spec.Selector {
+		matchLabels[key] = value
+	}
+	blocks := make([]interface{}, len(ipRanges))
+	for i, ipRange := range ipRanges {
+		blocks[i] = ipRange
+	}
+	sourceField := "remoteIpBlocks"
+	if spec.IPSource == networkingv1alpha1.IPSourcePeer {
+		sourceField = "ipBlocks"
+	}
+
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(AuthorizationPolicyGVK)
+	policy.SetNamespace(watchedRoute.Namespace)
+	policy.SetName(getAuthorizationPolicyName(cr, watchedRoute))
+
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, policy, func() error {
+		owner := string(cr.GetUID())
+		policyLabels := policy.GetLabels()
+		if policy.GetResourceVersion() != "" && policyLabels[AuthorizationPolicyOwnerLabel] != owner {
+			return fmt.Errorf("AuthorizationPolicy %s already exists and wasn't generated for %s", client.ObjectKeyFromObject(policy),
+				getOwnerKey(cr))
+		}
+
+		if policyLabels == nil {
+			policyLabels = make(map[string]string)
+		}
+		policyLabels[backend.ManagedByLabel] = backend.ManagedByValue
+		policyLabels[AuthorizationPolicyOwnerLabel] = owner
+		policy.SetLabels(policyLabels)
+
+		policy.Object["spec"] = map[string]interface{}{
+			"selector": map[string]interface{}{"matchLabels": matchLabels},
+			"action":   "ALLOW",
+			"rules": []interface{}{
+				map[string]interface{}{
+					"from": []interface{}{
+						map[string]interface{}{"source": map[string]interface{}{sourceField: blocks}},
+					},
+				},
+			},
+		}
+
+		// Owner references can't cross namespaces, the reconciler deletes the policies through the owner label
+		// regardless
+		if cr.GetNamespace() == "" || cr.GetNamespace() == policy.GetNamespace() {
+			return controllerutil.SetOwnerReference(cr, policy, r.Client.Scheme())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	key := client.ObjectKeyFromObject(policy)
+	return &key, nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+)
+
+// ClusterRouteAllowlistReconciler reconciles ClusterRouteAllowlists with the same engine as RouteAllowlists.
+// Backups of the original allowlists are kept in the config map of the watch namespace.
+type ClusterRouteAllowlistReconciler struct {
+	RouteAllowlistReconciler
+}
+
+//+kubebuilder:rbac:groups=networking.stakater.com,resources=clusterrouteallowlists,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.stakater.com,resources=clusterrouteallowlists/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=networking.stakater.com,resources=clusterrouteallowlists/finalizers,verbs=update;patch
+
+func (r *ClusterRouteAllowlistReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return r.reconcile(ctx, req, &networkingv1alpha1.ClusterRouteAllowlist{})
+}
+
+// mapTargetToClusterRouteAllowlist enqueues the ClusterRouteAllowlists when an enabled target changes
+func (r *ClusterRouteAllowlistReconciler) mapTargetToClusterRouteAllowlist(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx).WithName("mapTargetToClusterRouteAllowlist")
+
+	if val, ok := obj.GetLabels()[IPShieldWatchedResourceLabel]; !ok || val != "true" {
+		return nil
+	}
+
+	allowlists := &networkingv1alpha1.ClusterRouteAllowlistList{}
+	err := r.List(ctx, allowlists)
+
+	if err != nil {
+		logger.Error(err, "failed to fetch crd list")
+		return nil
+	}
+
+	result := make([]reconcile.Request, len(allowlists.Items))
+	for i, crd := range allowlists.Items {
+		result[i] = reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&crd)}
+	}
+
+	return result
+}
+
+// mapIPSetToClusterRouteAllowlist enqueues the ClusterRouteAllowlists referencing an IPSet of the watch namespace
+func (r *ClusterRouteAllowlistReconciler) mapIPSetToClusterRouteAllowlist(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx).WithName("mapIPSetToClusterRouteAllowlist")
+
+	if obj.GetNamespace() != r.WatchNamespace {
+		return nil
+	}
+
+	allowlists := &networkingv1alpha1.ClusterRouteAllowlistList{}
+	err := r.List(ctx, allowlists)
+
+	if err != nil {
+		logger.Error(err, "failed to fetch crd list")
+		return nil
+	}
+
+	var result []reconcile.Request
+	for _, crd := range allowlists.Items {
+		if slices.Contains(crd.Spec.IPSetRefs, obj.GetName()) {
+			result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&crd)})
+		}
+	}
+
+	return result
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterRouteAllowlistReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return r.watchTargets(ctrl.NewControllerManagedBy(mgr).
+		For(&networkingv1alpha1.ClusterRouteAllowlist{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})), r.mapTargetToClusterRouteAllowlist).
+		// Reconcile the allowlists referencing an IPSet when its ranges change
+		Watches(&networkingv1alpha1.IPSet{}, handler.EnqueueRequestsFromMapFunc(r.mapIPSetToClusterRouteAllowlist),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Namespaces entering or leaving a namespace selector change the selected routes
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.mapNamespaceToClusterRouteAllowlist),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+)
+
+// legacyConditionTypes were reported by previous releases and are removed from the status
+var legacyConditionTypes = []string{
+	"AllowlistReconciling", "Updating", "Admitted", "NoRoutesFound", "Deleted",
+	"RouteFetchError", "NamespaceFetchError", "IPSetFetchError", "ConfigMapFetchFailure",
+	"ConfigMapUpdateFailure", "RouteUpdateFailure", "RouteDeleteFailure",
+}
+
+func setCondition(cr allowlistObject, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&cr.GetStatus().Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: cr.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+func removeLegacyConditions(cr allowlistObject) {
+	for _, conditionType := range legacyConditionTypes {
+		apimeta.RemoveStatusCondition(&cr.GetStatus().Conditions, conditionType)
+	}
+}
+
+// setReady reports the allowlist as applied
+func setReady(cr allowlistObject, reason, message string) {
+	setCondition(cr, networkingv1alpha1.ConditionReady, metav1.ConditionTrue, reason, message)
+	setCondition(cr, networkingv1alpha1.ConditionProgressing, metav1.ConditionFalse, networkingv1alpha1.ReasonReconciled, "Reconciliation complete")
+	setCondition(cr, networkingv1alpha1.ConditionDegraded, metav1.ConditionFalse, networkingv1alpha1.ReasonAsExpected, "No failure")
+}
+
+// setDegraded reports the failure preventing the allowlist from being applied, which is retried
+func setDegraded(cr allowlistObject, reason string, err error) {
+	setCondition(cr, networkingv1alpha1.ConditionReady, metav1.ConditionFalse, reason, err.Error())
+	setCondition(cr, networkingv1alpha1.ConditionProgressing, metav1.ConditionTrue, networkingv1alpha1.ReasonRetrying, "Retrying after failure")
+	setCondition(cr, networkingv1alpha1.ConditionDegraded, metav1.ConditionTrue, reason, err.Error())
+}
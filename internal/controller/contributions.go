@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+)
+
+// ContributionsAnnotation records on each route which RouteAllowlist contributed which ranges to its allowlist
+const ContributionsAnnotation = "ipshield.stakater.cloud/contributions"
+
+// contribution is the set of ranges a single RouteAllowlist applied to a route
+type contribution struct {
+	Owner  string   `json:"owner"`
+	Ranges []string `json:"ranges"`
+	// Replace is set when the RouteAllowlist uses the Replace strategy
+	Replace bool `json:"replace,omitempty"`
+}
+
+type contributions []contribution
+
+func getContributions(annotations map[string]string) (contributions, error) {
+	value, ok := annotations[ContributionsAnnotation]
+	if !ok || value == "" {
+		return nil, nil
+	}
+
+	var result contributions
+	if err := json.Unmarshal([]byte(value), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s: %w", ContributionsAnnotation, err)
+	}
+	return result, nil
+}
+
+// setContributions writes the contributions sorted by owner, so the annotation only changes when a contribution does
+func setContributions(annotations map[string]string, c contributions) error {
+	if len(c) == 0 {
+		delete(annotations, ContributionsAnnotation)
+		return nil
+	}
+
+	sorted := slices.Clone(c)
+	slices.SortFunc(sorted, func(a, b contribution) int { return strings.Compare(a.Owner, b.Owner) })
+
+	value, err := json.Marshal(sorted)
+	if err != nil {
+		return err
+	}
+	annotations[ContributionsAnnotation] = string(value)
+	return nil
+}
+
+// with returns the contributions where the ranges of owner are replaced by the given ranges
+func (c contributions) with(owner string, ranges []string, strategy networkingv1alpha1.Strategy) contributions {
+	return append(c.without(owner), contribution{Owner: owner, Ranges: ranges, Replace: strategy == networkingv1alpha1.StrategyReplace})
+}
+
+// without returns the contributions where the ranges of owner are withdrawn
+func (c contributions) without(owner string) contributions {
+	result := make(contributions, 0, len(c))
+	for _, item := range c {
+		if item.Owner != owner {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// live returns the contributions whose owner still exists and selects the target. Contributions are read from an
+// annotation of the target, so entries of other allowlists are never trusted.
+func (c contributions) live(owners liveOwners, kind networkingv1alpha1.TargetKind, target client.Object) contributions {
+	result := make(contributions, 0, len(c))
+	for _, item := range c {
+		if owners.selects(item.Owner, kind, target) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func (c contributions) ranges() []string {
+	var result []string
+	for _, item := range c {
+		result = append(result, item.Ranges...)
+	}
+	return result
+}
+
+// replaces reports whether any contribution uses the Replace strategy
+func (c contributions) replaces() bool {
+	return slices.ContainsFunc(c, func(item contribution) bool { return item.Replace })
+}
+
+// computeAllowlist returns the allowlist of a route as the union of its original value and the ranges of every
+// contribution. Ranges added to the annotation outside of IPShield are not part of it. When any contribution
+// replaces the allowlist, only the ranges of the contributions are kept.
+func computeAllowlist(original string, next contributions) string {
+	if next.replaces() {
+		return mergeSet(nil, next.ranges())
+	}
+	return mergeSet(strings.Fields(original), next.ranges())
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+	"github.com/stakater/ipshield-operator/pkg/backend"
+	"github.com/stakater/ipshield-operator/pkg/cidr"
+)
+
+// getDrift returns the changes made to the allowlist of a route outside of IPShield since the previous
+// contributions were applied, nil if there is none. Any range that is neither part of the original allowlist
+// backed up in the config map nor contributed is drift, as well as any of these ranges that was removed.
+func getDrift(current []string, original string, previous contributions) *networkingv1alpha1.AllowlistChanges {
+	return getChanges(strings.Fields(computeAllowlist(original, previous)), cidr.Merge(current))
+}
+
+// getNextAllowlist returns the allowlist to write to a route along with its drift. With the Enforce drift policy
+// the allowlist is recomputed, which reverts the drift. Otherwise the drift is kept and only the changes between
+// the previous and the next contributions are applied to the current allowlist.
+func getNextAllowlist(policy networkingv1alpha1.DriftPolicy, current []string, original string, previous, next contributions) (string, *networkingv1alpha1.AllowlistChanges) {
+	drift := getDrift(current, original, previous)
+	if drift == nil || policy == networkingv1alpha1.DriftPolicyEnforce || policy == "" {
+		return computeAllowlist(original, next), drift
+	}
+
+	retracted := cidr.Subtract(previous.ranges(), next.ranges())
+	added := cidr.Subtract(next.ranges(), previous.ranges())
+	return mergeSet(cidr.Subtract(current, retracted), added), drift
+}
+
+// getRemainingDriftPolicy returns the drift policy applied once an allowlist stops managing a route, Enforce if
+// any of the remaining allowlists enforces it and Ignore otherwise, as Report doesn't revert the drift either
+func getRemainingDriftPolicy(owners liveOwners, next contributions) networkingv1alpha1.DriftPolicy {
+	for _, item := range next {
+		if owner, ok := owners[item.Owner]; ok {
+			policy := owner.cr.GetSpec().DriftPolicy
+			if policy == networkingv1alpha1.DriftPolicyEnforce || policy == "" {
+				return networkingv1alpha1.DriftPolicyEnforce
+			}
+		}
+	}
+	return networkingv1alpha1.DriftPolicyIgnore
+}
+
+// reportDrift records the drift of the route in its status with the Report drift policy, and emits an Event
+// on the route when it differs from the drift recorded previously
+func (r *RouteAllowlistReconciler) reportDrift(cr allowlistObject, b backend.Backend, watchedRoute client.Object,
+	previous, drift *networkingv1alpha1.AllowlistChanges) {
+	routeStatus := getRouteStatus(cr, b, watchedRoute)
+	if routeStatus == nil || cr.GetSpec().DriftPolicy != networkingv1alpha1.DriftPolicyReport {
+		return
+	}
+	routeStatus.Drift = drift
+
+	if drift != nil && !equalChanges(previous, drift) {
+		r.recordEvent(watchedRoute, corev1.EventTypeWarning, networkingv1alpha1.ConditionDriftDetected,
+			"Allowlist modified outside of IPShield, added: [%s], removed: [%s], reported by allowlist %s",
+			strings.Join(drift.Added, " "), strings.Join(drift.Removed, " "), getOwnerKey(cr))
+	}
+}
+
+// removeDriftCondition removes the DriftDetected condition, for modes that don't look for drift
+func removeDriftCondition(cr allowlistObject) {
+	apimeta.RemoveStatusCondition(&cr.GetStatus().Conditions, networkingv1alpha1.ConditionDriftDetected)
+}
+
+// setDriftCondition sets the DriftDetected condition from the drift recorded in the status of the routes,
+// the condition is removed unless the drift policy is Report
+func setDriftCondition(cr allowlistObject) {
+	if cr.GetSpec().DriftPolicy != networkingv1alpha1.DriftPolicyReport {
+		removeDriftCondition(cr)
+		return
+	}
+
+	var drifted []string
+	for _, routeStatus := range cr.GetStatus().Routes {
+		if routeStatus.Drift != nil {
+			drifted = append(drifted, routeStatus.Namespace+"/"+routeStatus.Name)
+		}
+	}
+
+	if len(drifted) == 0 {
+		setCondition(cr, networkingv1alpha1.ConditionDriftDetected, metav1.ConditionFalse, networkingv1alpha1.ReasonNoDrift,
+			"No allowlist was modified outside of IPShield")
+		return
+	}
+	setCondition(cr, networkingv1alpha1.ConditionDriftDetected, metav1.ConditionTrue, networkingv1alpha1.ReasonAllowlistModified,
+		fmt.Sprintf("Allowlist of %d route(s) modified outside of IPShield: %s", len(drifted), strings.Join(drifted, ", ")))
+}
+
+// getRequeueAfter returns the earliest of the given delay and the resync period, ignoring those that are zero
+func (r *RouteAllowlistReconciler) getRequeueAfter(next time.Duration) time.Duration {
+	if r.ResyncPeriod > 0 && (next == 0 || r.ResyncPeriod < next) {
+		return r.ResyncPeriod
+	}
+	return next
+}
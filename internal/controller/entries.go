@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+func (r *RouteAllowlistReconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
+}
+
+// getEntryState returns the state of the entry at the given time
+func getEntryState(entry networkingv1alpha1.IPRangeEntry, now time.Time) networkingv1alpha1.IPRangeEntryState {
+	switch {
+	case entry.ExpiresAt != nil && !now.Before(entry.ExpiresAt.Time):
+		return networkingv1alpha1.IPRangeEntryExpired
+	case entry.NotBefore != nil && now.Before(entry.NotBefore.Time):
+		return networkingv1alpha1.IPRangeEntryPending
+	default:
+		return networkingv1alpha1.IPRangeEntryActive
+	}
+}
+
+// getActiveEntries returns the ranges of the entries active at the given time
+func getActiveEntries(entries []networkingv1alpha1.IPRangeEntry, now time.Time) []string {
+	var result []string
+	for _, entry := range entries {
+		if getEntryState(entry, now) == networkingv1alpha1.IPRangeEntryActive {
+			result = append(result, entry.CIDR)
+		}
+	}
+	return result
+}
+
+// getNextEntryTransition returns the time until the next entry changes state, zero if no entry will
+func getNextEntryTransition(entries []networkingv1alpha1.IPRangeEntry, now time.Time) time.Duration {
+	var next time.Duration
+	for _, entry := range entries {
+		for _, boundary := range []*metav1.Time{entry.NotBefore, entry.ExpiresAt} {
+			if boundary == nil || !boundary.After(now) {
+				continue
+			}
+			if until := boundary.Sub(now); next == 0 || until < next {
+				next = until
+			}
+		}
+	}
+	return next
+}
+
+// updateEntryStatus records the state of the entries in the status of the CR and emits an event for every
+// entry that expired since the last reconciliation. Entries added after they expired were never applied
+// and don't emit any event.
+func (r *RouteAllowlistReconciler) updateEntryStatus(cr allowlistObject, now time.Time) {
+	status := cr.GetStatus()
+
+	var entries []networkingv1alpha1.IPRangeEntryStatus
+	for _, entry := range cr.GetSpec().Entries {
+		state := getEntryState(entry, now)
+		// Entries are matched as a whole, several entries may allow the same range within different windows
+		i := slices.IndexFunc(status.Entries, func(previous networkingv1alpha1.IPRangeEntryStatus) bool {
+			return equality.Semantic.DeepEqual(previous.IPRangeEntry, entry)
+		})
+		if i >= 0 && status.Entries[i].State != state && state == networkingv1alpha1.IPRangeEntryExpired {
+			r.recordEvent(cr, corev1.EventTypeNormal, "EntryExpired", "IP range %s expired at %s and was retracted from the routes",
+				entry.CIDR, entry.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+
+		entries = append(entries, networkingv1alpha1.IPRangeEntryStatus{IPRangeEntry: entry, State: state})
+	}
+	status.Entries = entries
+}
+
+// entryRecord documents an entry applied to a route by an allowlist, so auditors can trace each range
+// of a route back to its description, owner and reference
+type entryRecord struct {
+	Allowlist string `json:"allowlist"`
+	networkingv1alpha1.IPRangeEntry
+}
+
+// getEntriesKey returns the key under which the entries applied to the route are stored in the config map.
+// Route and ingress names can't contain underscores, so the key never collides with the key of another object.
+func getEntriesKey(watchedRoute client.Object) string {
+	return getRouteFullName(watchedRoute) + "__entries"
+}
+
+// getAppliedEntries returns the entries of the CR that are currently applied to its routes
+func getAppliedEntries(cr allowlistObject) []networkingv1alpha1.IPRangeEntry {
+	var result []networkingv1alpha1.IPRangeEntry
+	for _, entry := range cr.GetStatus().Entries {
+		if entry.State == networkingv1alpha1.IPRangeEntryActive {
+			result = append(result, entry.IPRangeEntry)
+		}
+	}
+	return result
+}
+
+// setEntryRecords replaces the records of owner under key by the given entries, dropping the records of
+// allowlists that don't exist anymore. The key is removed once no record is left.
+func setEntryRecords(data map[string]string, key, owner string, entries []networkingv1alpha1.IPRangeEntry, liveOwners liveOwners) error {
+	var records []entryRecord
+	if value, ok := data[key]; ok && value != "" {
+		if err := json.Unmarshal([]byte(value), &records); err != nil {
+			return fmt.Errorf("failed to parse config map key %s: %w", key, err)
+		}
+	}
+
+	records = slices.DeleteFunc(records, func(record entryRecord) bool {
+		return record.Allowlist == owner || !liveOwners.contains(record.Allowlist)
+	})
+	for _, entry := range entries {
+		records = append(records, entryRecord{Allowlist: owner, IPRangeEntry: entry})
+	}
+
+	if len(records) == 0 {
+		delete(data, key)
+		return nil
+	}
+
+	slices.SortStableFunc(records, func(a, b entryRecord) int {
+		if c := strings.Compare(a.Allowlist, b.Allowlist); c != 0 {
+			return c
+		}
+		return strings.Compare(a.CIDR, b.CIDR)
+	})
+
+	value, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	data[key] = string(value)
+	return nil
+}
+
+// updateEntryRecords records the entries the CR applied to the route in the config map
+func (r *RouteAllowlistReconciler) updateEntryRecords(ctx context.Context, watchedRoute client.Object, cr allowlistObject,
+	configMap *corev1.ConfigMap, liveOwners liveOwners) error {
+	patchBase := client.MergeFrom(configMap.DeepCopy())
+
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+
+	if err := setEntryRecords(configMap.Data, getEntriesKey(watchedRoute), getOwnerKey(cr), getAppliedEntries(cr), liveOwners); err != nil {
+		return err
+	}
+
+	return r.patchIfChanged(ctx, configMap, patchBase)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+	"github.com/stakater/ipshield-operator/pkg/cidr"
+)
+
+//+kubebuilder:rbac:groups=networking.stakater.com,resources=ipsets,verbs=get;list;watch
+
+// getIPRanges returns the canonical ranges of the CR, including the ranges of the IPSets it references
+// and of the entries active at the given time
+func (r *RouteAllowlistReconciler) getIPRanges(ctx context.Context, cr allowlistObject, now time.Time) ([]string, error) {
+	ipRanges := append(slices.Clone(cr.GetSpec().IPRanges), getActiveEntries(cr.GetSpec().Entries, now)...)
+
+	// ClusterRouteAllowlists reference the IPSets of the watch namespace
+	namespace := cr.GetNamespace()
+	if namespace == "" {
+		namespace = r.WatchNamespace
+	}
+
+	for _, ref := range cr.GetSpec().IPSetRefs {
+		ipSet := &networkingv1alpha1.IPSet{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref}, ipSet); err != nil {
+			return nil, fmt.Errorf("failed to get IPSet '%s': %w", ref, err)
+		}
+
+		for _, ipSetRange := range ipSet.Spec.Ranges {
+			ipRanges = append(ipRanges, ipSetRange.CIDR)
+		}
+	}
+
+	return cidr.Merge(ipRanges), nil
+}
+
+// mapIPSetToRouteAllowlist enqueues the RouteAllowlists referencing the IPSet
+func (r *RouteAllowlistReconciler) mapIPSetToRouteAllowlist(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx).WithName("mapIPSetToRouteAllowlist")
+
+	allowlists := &networkingv1alpha1.RouteAllowlistList{}
+	err := r.List(ctx, allowlists, client.InNamespace(obj.GetNamespace()))
+
+	if err != nil {
+		logger.Error(err, "failed to fetch crd list")
+		return nil
+	}
+
+	var result []reconcile.Request
+	for _, crd := range allowlists.Items {
+		if slices.Contains(crd.Spec.IPSetRefs, obj.GetName()) {
+			result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&crd)})
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	set "github.com/deckarep/golang-set/v2"
+	route "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+	"github.com/stakater/ipshield-operator/pkg/backend"
+)
+
+// maxReportedViolations bounds the number of violations listed in the status of a policy
+const maxReportedViolations = 100
+
+// IPShieldPolicyReconciler reports the routes violating IPShieldPolicies
+type IPShieldPolicyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=networking.stakater.com,resources=ipshieldpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.stakater.com,resources=ipshieldpolicies/status,verbs=get;update;patch
+
+func (r *IPShieldPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithName("ipShieldPolicy-controller")
+	logger.Info("Reconciling IPShieldPolicy")
+
+	policy := &networkingv1alpha1.IPShieldPolicy{}
+	if err := r.Get(ctx, req.NamespacedName, policy); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	patchBase := client.MergeFrom(policy.DeepCopy())
+	policy.Status.ObservedGeneration = policy.Generation
+
+	namespaceSelector, err := metav1.LabelSelectorAsSelector(policy.Spec.NamespaceSelector)
+	if err != nil {
+		return r.patchPolicyErrorStatus(ctx, policy, patchBase, networkingv1alpha1.ReasonInvalidSpec, err)
+	}
+	routeSelector, err := getRouteSelector(policy)
+	if err != nil {
+		return r.patchPolicyErrorStatus(ctx, policy, patchBase, networkingv1alpha1.ReasonInvalidSpec, err)
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err = r.List(ctx, namespaces, &client.ListOptions{LabelSelector: namespaceSelector}); err != nil {
+		return r.patchPolicyErrorStatus(ctx, policy, patchBase, networkingv1alpha1.ReasonNamespaceFetchFailed, err)
+	}
+	selected := set.NewSet[string]()
+	for _, namespace := range namespaces.Items {
+		selected.Add(namespace.Name)
+	}
+
+	routes := &route.RouteList{}
+	if err = r.List(ctx, routes, &client.ListOptions{LabelSelector: routeSelector}); err != nil {
+		return r.patchPolicyErrorStatus(ctx, policy, patchBase, networkingv1alpha1.ReasonRouteFetchFailed, err)
+	}
+	filterRoutesByNamespace(routes, selected)
+
+	var violations []networkingv1alpha1.PolicyViolation
+	for _, item := range routes.Items {
+		if reason := getPolicyViolation(&item); reason != "" {
+			violations = append(violations, networkingv1alpha1.PolicyViolation{Namespace: item.Namespace, Name: item.Name, Reason: reason})
+		}
+	}
+
+	slices.SortFunc(violations, func(a, b networkingv1alpha1.PolicyViolation) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	policy.Status.ViolationCount = int32(len(violations))
+	policy.Status.Violations = violations[:min(len(violations), maxReportedViolations)]
+
+	if len(violations) == 0 {
+		setPolicyCondition(policy, metav1.ConditionTrue, networkingv1alpha1.ReasonNoViolations, "Every selected route carries an allowlist")
+	} else {
+		setPolicyCondition(policy, metav1.ConditionFalse, networkingv1alpha1.ReasonViolationsFound,
+			fmt.Sprintf("%d route(s) don't carry an allowlist", len(violations)))
+	}
+
+	return ctrl.Result{}, r.Status().Patch(ctx, policy, patchBase)
+}
+
+func (r *IPShieldPolicyReconciler) patchPolicyErrorStatus(ctx context.Context, policy *networkingv1alpha1.IPShieldPolicy,
+	patch client.Patch, reason string, err error) (ctrl.Result, error) {
+	setPolicyCondition(policy, metav1.ConditionUnknown, reason, err.Error())
+	if patchErr := r.Status().Patch(ctx, policy, patch); patchErr != nil {
+		return ctrl.Result{}, patchErr
+	}
+	return ctrl.Result{}, err
+}
+
+func setPolicyCondition(policy *networkingv1alpha1.IPShieldPolicy, status metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{
+		Type:               networkingv1alpha1.ConditionCompliant,
+		Status:             status,
+		ObservedGeneration: policy.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// getRouteSelector returns the selector of the routes of the policy, every route if not set
+func getRouteSelector(policy *networkingv1alpha1.IPShieldPolicy) (labels.Selector, error) {
+	if policy.Spec.RouteSelector == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(policy.Spec.RouteSelector)
+}
+
+// getPolicyViolation returns why the route doesn't carry an IPShield allowlist, empty if it does
+func getPolicyViolation(watchedRoute *route.Route) string {
+	if val, ok := watchedRoute.Labels[IPShieldWatchedResourceLabel]; !ok || val != "true" {
+		return fmt.Sprintf("label %s=true is missing", IPShieldWatchedResourceLabel)
+	}
+
+	previous, err := getContributions(watchedRoute.Annotations)
+	if err != nil {
+		return err.Error()
+	}
+	if len(previous) == 0 {
+		return "no RouteAllowlist or ClusterRouteAllowlist selects the route"
+	}
+
+	if mergeSet(backend.GetRouteAllowlist(watchedRoute.Annotations), nil) == "" {
+		return "the allowlist of the route is empty"
+	}
+	return ""
+}
+
+// PolicyViolationError names the IPShieldPolicies with the Deny action a route violates
+type PolicyViolationError struct {
+	Policies []string
+	Reason   string
+}
+
+func (e *PolicyViolationError) Error() string {
+	return fmt.Sprintf("the route violates IPShieldPolicy %s: %s", strings.Join(e.Policies, ", "), e.Reason)
+}
+
+// ValidateRoute returns a PolicyViolationError when the route violates IPShieldPolicies with the Deny action, any
+// other error means the policies couldn't be evaluated
+func (r *IPShieldPolicyReconciler) ValidateRoute(ctx context.Context, watchedRoute *route.Route) error {
+	reason := getPolicyViolation(watchedRoute)
+	if reason == "" {
+		return nil
+	}
+
+	policies := &networkingv1alpha1.IPShieldPolicyList{}
+	if err := r.List(ctx, policies); err != nil {
+		return err
+	}
+
+	denying := slices.DeleteFunc(policies.Items, func(policy networkingv1alpha1.IPShieldPolicy) bool {
+		return policy.Spec.EnforcementAction == networkingv1alpha1.EnforcementActionAudit
+	})
+	if len(denying) == 0 {
+		return nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: watchedRoute.Namespace}, namespace); err != nil {
+		return err
+	}
+
+	var violated []string
+	for _, policy := range denying {
+		namespaceSelector, err := metav1.LabelSelectorAsSelector(policy.Spec.NamespaceSelector)
+		if err != nil {
+			return err
+		}
+		routeSelector, err := getRouteSelector(&policy)
+		if err != nil {
+			return err
+		}
+
+		if namespaceSelector.Matches(labels.Set(namespace.Labels)) && routeSelector.Matches(labels.Set(watchedRoute.Labels)) {
+			violated = append(violated, policy.Name)
+		}
+	}
+
+	if len(violated) == 0 {
+		return nil
+	}
+	return &PolicyViolationError{Policies: violated, Reason: reason}
+}
+
+// mapToIPShieldPolicies enqueues every policy, as any of them may select the route or namespace that changed
+func (r *IPShieldPolicyReconciler) mapToIPShieldPolicies(ctx context.Context, _ client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx).WithName("mapToIPShieldPolicies")
+
+	policies := &networkingv1alpha1.IPShieldPolicyList{}
+	if err := r.List(ctx, policies); err != nil {
+		logger.Error(err, "failed to fetch policy list")
+		return nil
+	}
+
+	result := make([]reconcile.Request, len(policies.Items))
+	for i, policy := range policies.Items {
+		result[i] = reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&policy)}
+	}
+	return result
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *IPShieldPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&networkingv1alpha1.IPShieldPolicy{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&route.Route{}, handler.EnqueueRequestsFromMapFunc(r.mapToIPShieldPolicies),
+			builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.mapToIPShieldPolicies),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	v1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+)
+
+var _ = Describe("IPShieldPolicy Controller", func() {
+
+	var (
+		ctx        context.Context
+		reconciler *IPShieldPolicyReconciler
+		policy     *networkingv1alpha1.IPShieldPolicy
+		fakeClient client.Client
+	)
+
+	newRoute := func(namespace, name string, enabled bool, annotations map[string]string) *v1.Route {
+		labels := map[string]string{"app": name}
+		if enabled {
+			labels[IPShieldWatchedResourceLabel] = "true"
+		}
+		return &v1.Route{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels, Annotations: annotations}}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		policy = &networkingv1alpha1.IPShieldPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "pci"},
+			Spec: networkingv1alpha1.IPShieldPolicySpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"compliance": "pci"}},
+				EnforcementAction: networkingv1alpha1.EnforcementActionDeny,
+			},
+		}
+
+		fakeClient = fakeclient.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(
+				policy,
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"compliance": "pci"}}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+				newRoute("payments", "protected", true, map[string]string{
+					AllowlistAnnotation:     "10.100.123.24",
+					ContributionsAnnotation: `[{"owner":"ipshield-cr/test-route","ranges":["10.100.123.24"]}]`,
+				}),
+				newRoute("payments", "unlabelled", false, nil),
+				newRoute("payments", "unselected", true, nil),
+				newRoute("default", "ignored", false, nil),
+			).
+			WithStatusSubresource(policy).
+			Build()
+
+		reconciler = &IPShieldPolicyReconciler{
+			Client: fakeClient,
+			Scheme: scheme.Scheme,
+		}
+	})
+
+	It("will test that the routes of the selected namespaces without an allowlist are reported", func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(policy)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), policy)).Should(Succeed())
+		Expect(policy.Status.ViolationCount).To(BeEquivalentTo(2))
+		Expect(policy.Status.Violations).To(Equal([]networkingv1alpha1.PolicyViolation{
+			{Namespace: "payments", Name: "unlabelled", Reason: "label ipshield.stakater.cloud/enabled=true is missing"},
+			{Namespace: "payments", Name: "unselected", Reason: "no RouteAllowlist or ClusterRouteAllowlist selects the route"},
+		}))
+		Expect(apimeta.FindStatusCondition(policy.Status.Conditions, networkingv1alpha1.ConditionCompliant)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionFalse),
+			"Reason": Equal(networkingv1alpha1.ReasonViolationsFound),
+		})))
+
+		By("Restricting the policy to the protected route")
+
+		policy.Spec.RouteSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "protected"}}
+		Expect(fakeClient.Update(ctx, policy)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(policy)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(policy), policy)).Should(Succeed())
+		Expect(policy.Status.ViolationCount).To(BeZero())
+		Expect(policy.Status.Violations).To(BeEmpty())
+		Expect(apimeta.IsStatusConditionTrue(policy.Status.Conditions, networkingv1alpha1.ConditionCompliant)).To(BeTrue())
+	})
+
+	It("will test that routes violating a denying policy are rejected", func() {
+		Expect(reconciler.ValidateRoute(ctx, newRoute("payments", "new", false, nil))).To(
+			MatchError("the route violates IPShieldPolicy pci: label ipshield.stakater.cloud/enabled=true is missing"))
+		Expect(reconciler.ValidateRoute(ctx, newRoute("default", "new", false, nil))).To(Succeed())
+		Expect(reconciler.ValidateRoute(ctx, newRoute("payments", "new", true, map[string]string{
+			AllowlistAnnotation:     "10.100.123.24",
+			ContributionsAnnotation: `[{"owner":"platform","ranges":["10.100.123.24"]}]`,
+		}))).To(Succeed())
+
+		By("Auditing the policy only")
+
+		policy.Spec.EnforcementAction = networkingv1alpha1.EnforcementActionAudit
+		Expect(fakeClient.Update(ctx, policy)).Should(Succeed())
+		Expect(reconciler.ValidateRoute(ctx, newRoute("payments", "new", false, nil))).To(Succeed())
+	})
+
+	It("will test that failures to evaluate the policies aren't reported as violations", func() {
+		err := reconciler.ValidateRoute(ctx, newRoute("missing", "new", false, nil))
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		var violation *PolicyViolationError
+		Expect(errors.As(err, &violation)).To(BeFalse())
+
+		By("Skipping the namespace without a denying policy")
+
+		policy.Spec.EnforcementAction = networkingv1alpha1.EnforcementActionAudit
+		Expect(fakeClient.Update(ctx, policy)).Should(Succeed())
+		Expect(reconciler.ValidateRoute(ctx, newRoute("missing", "new", false, nil))).To(Succeed())
+	})
+})
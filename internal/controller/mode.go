@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	set "github.com/deckarep/golang-set/v2"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+	"github.com/stakater/ipshield-operator/pkg/cidr"
+)
+
+// handlePaused only refreshes the status, routes that are no longer selected are kept in it so they are
+// restored once the allowlist is enforced again
+func (r *RouteAllowlistReconciler) handlePaused(ctx context.Context, selections []selection, cr allowlistObject,
+	patch client.Patch, logger logr.Logger) (ctrl.Result, error) {
+	updateRouteCounters(cr, countSelected(selections))
+	removeDriftCondition(cr)
+	setReady(cr, networkingv1alpha1.ReasonPaused, "Reconciliation is paused, routes are not updated")
+	return ctrl.Result{}, r.patchResourceAndStatus(ctx, cr, patch, logger)
+}
+
+// handleDryRun computes the allowlist handleUpdate would apply to each route and reports the difference with
+// the current one in the status of the route and as an Event. Neither the routes nor the config maps are updated.
+func (r *RouteAllowlistReconciler) handleDryRun(ctx context.Context, selections []selection, ipRanges []string,
+	cr allowlistObject, patch client.Patch, logger logr.Logger) (ctrl.Result, error) {
+	liveOwners, err := r.getLiveOwners(ctx)
+	if err != nil {
+		setDegraded(cr, networkingv1alpha1.ReasonAllowlistFetchFailed, err)
+		return r.patchErrorStatus(ctx, cr, patch, err)
+	}
+
+	pending := 0
+	for _, s := range selections {
+		if len(s.selected) == 0 {
+			continue
+		}
+
+		configMap := &corev1.ConfigMap{}
+		err = r.Get(ctx, types.NamespacedName{Name: s.backend.ConfigMapName(), Namespace: r.WatchNamespace}, configMap)
+		if err != nil && !errors.IsNotFound(err) {
+			setDegraded(cr, networkingv1alpha1.ReasonConfigMapUpdateFailed, fmt.Errorf("failed to get config map: %w", err))
+			return r.patchErrorStatus(ctx, cr, patch, err)
+		}
+
+		for _, watchedRoute := range s.selected {
+			if val, ok := watchedRoute.GetLabels()[IPShieldWatchedResourceLabel]; !ok || val != "true" {
+				removeRouteStatus(cr, s.backend, watchedRoute)
+				continue
+			}
+
+			current, err := s.backend.GetAllowlist(ctx, r.Client, watchedRoute)
+			var changes *networkingv1alpha1.AllowlistChanges
+			if err == nil {
+				changes, err = r.getPendingChanges(s.backend.Kind(), watchedRoute, current, ipRanges, cr, configMap, liveOwners)
+			}
+			if err != nil {
+				logger.Error(err, "failed to read route allowlist", "kind", s.backend.Kind(), "route", client.ObjectKeyFromObject(watchedRoute))
+			}
+
+			routeStatus := networkingv1alpha1.RouteStatus{
+				Kind:           s.backend.Kind(),
+				Namespace:      watchedRoute.GetNamespace(),
+				Name:           watchedRoute.GetName(),
+				Host:           s.backend.GetHost(watchedRoute),
+				Allowlist:      mergeSet(current, nil),
+				PendingChanges: changes,
+			}
+			var previous *networkingv1alpha1.AllowlistChanges
+			if previousStatus := getRouteStatus(cr, s.backend, watchedRoute); previousStatus != nil {
+				// Nothing is applied in DryRun mode
+				routeStatus.LastAppliedTime = previousStatus.LastAppliedTime
+				previous = previousStatus.PendingChanges
+			}
+			if err != nil {
+				routeStatus.Error = err.Error()
+			}
+			putRouteStatus(cr, routeStatus)
+
+			if changes == nil {
+				continue
+			}
+			pending++
+			if !equalChanges(previous, changes) {
+				r.recordEvent(cr, corev1.EventTypeNormal, networkingv1alpha1.ReasonDryRun,
+					"%s %s would be updated, added: [%s], removed: [%s]", s.backend.Kind(), client.ObjectKeyFromObject(watchedRoute),
+					strings.Join(changes.Added, " "), strings.Join(changes.Removed, " "))
+			}
+		}
+	}
+
+	updateRouteCounters(cr, countSelected(selections))
+	removeDriftCondition(cr)
+	setReady(cr, networkingv1alpha1.ReasonDryRun, fmt.Sprintf("Dry run, %d route(s) would be updated", pending))
+	return ctrl.Result{}, r.patchResourceAndStatus(ctx, cr, patch, logger)
+}
+
+// getPendingChanges returns the ranges handleUpdate would add to and remove from the allowlist of the route,
+// nil when the allowlist is already applied
+func (r *RouteAllowlistReconciler) getPendingChanges(kind networkingv1alpha1.TargetKind, watchedRoute client.Object, current []string, ipRanges []string,
+	cr allowlistObject, configMap *corev1.ConfigMap, liveOwners liveOwners) (*networkingv1alpha1.AllowlistChanges, error) {
+	previous, err := getContributions(watchedRoute.GetAnnotations())
+	if err != nil {
+		return nil, err
+	}
+
+	original, ok := configMap.Data[getRouteFullName(watchedRoute)]
+	if !ok {
+		original = diffSet(current, previous.ranges())
+	}
+
+	next := previous.live(liveOwners, kind, watchedRoute).with(getOwnerKey(cr), ipRanges, cr.GetSpec().Strategy)
+	expected, _ := getNextAllowlist(cr.GetSpec().DriftPolicy, current, original, previous, next)
+	return getChanges(cidr.Merge(current), strings.Fields(expected)), nil
+}
+
+// getChanges returns the ranges of to that are not part of from as added and the ranges of from that are
+// not part of to as removed, nil when both lists hold the same ranges
+func getChanges(from, to []string) *networkingv1alpha1.AllowlistChanges {
+	fromSet, toSet := set.NewSet(from...), set.NewSet(to...)
+
+	added, removed := toSet.Difference(fromSet).ToSlice(), fromSet.Difference(toSet).ToSlice()
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return &networkingv1alpha1.AllowlistChanges{Added: added, Removed: removed}
+}
+
+func equalChanges(a, b *networkingv1alpha1.AllowlistChanges) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return slices.Equal(a.Added, b.Added) && slices.Equal(a.Removed, b.Removed)
+}
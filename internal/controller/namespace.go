@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	set "github.com/deckarep/golang-set/v2"
+	route "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+
+// getSelectedNamespaces returns the namespaces the routes of the CR are selected from, or nil when the CR
+// isn't restricted to any namespace
+func (r *RouteAllowlistReconciler) getSelectedNamespaces(ctx context.Context, cr allowlistObject) (set.Set[string], error) {
+	spec := cr.GetSpec()
+	if spec.NamespaceSelector == nil && len(spec.Namespaces) == 0 {
+		return nil, nil
+	}
+
+	var selected set.Set[string]
+	if len(spec.Namespaces) > 0 {
+		selected = set.NewSet(spec.Namespaces...)
+	}
+
+	if spec.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.NamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		namespaces := &corev1.NamespaceList{}
+		if err = r.List(ctx, namespaces, &client.ListOptions{LabelSelector: selector}); err != nil {
+			return nil, err
+		}
+
+		matching := set.NewSet[string]()
+		for _, namespace := range namespaces.Items {
+			matching.Add(namespace.Name)
+		}
+
+		if selected == nil {
+			selected = matching
+		} else {
+			selected = selected.Intersect(matching)
+		}
+	}
+
+	return selected, nil
+}
+
+// filterRoutesByNamespace drops the routes outside of the selected namespaces
+func filterRoutesByNamespace(routes *route.RouteList, namespaces set.Set[string]) {
+	if namespaces == nil {
+		return
+	}
+
+	routes.Items = slices.DeleteFunc(routes.Items, func(item route.Route) bool {
+		return !namespaces.Contains(item.Namespace)
+	})
+}
+
+// mapNamespaceToRouteAllowlist enqueues the RouteAllowlists selecting namespaces by label, whose routes may
+// have changed with the labels of the namespace
+func (r *RouteAllowlistReconciler) mapNamespaceToRouteAllowlist(ctx context.Context, _ client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx).WithName("mapNamespaceToRouteAllowlist")
+
+	allowlists := &networkingv1alpha1.RouteAllowlistList{}
+	err := r.List(ctx, allowlists)
+
+	if err != nil {
+		logger.Error(err, "failed to fetch crd list")
+		return nil
+	}
+
+	var result []reconcile.Request
+	for _, crd := range allowlists.Items {
+		if crd.Spec.NamespaceSelector != nil {
+			result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&crd)})
+		}
+	}
+
+	return result
+}
+
+// mapNamespaceToClusterRouteAllowlist enqueues the ClusterRouteAllowlists selecting namespaces by label
+func (r *ClusterRouteAllowlistReconciler) mapNamespaceToClusterRouteAllowlist(ctx context.Context, _ client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx).WithName("mapNamespaceToClusterRouteAllowlist")
+
+	allowlists := &networkingv1alpha1.ClusterRouteAllowlistList{}
+	err := r.List(ctx, allowlists)
+
+	if err != nil {
+		logger.Error(err, "failed to fetch crd list")
+		return nil
+	}
+
+	var result []reconcile.Request
+	for _, crd := range allowlists.Items {
+		if crd.Spec.NamespaceSelector != nil {
+			result = append(result, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&crd)})
+		}
+	}
+
+	return result
+}
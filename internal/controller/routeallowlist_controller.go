@@ -17,15 +17,21 @@ limitations under the License.
 package controller
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
-	route "github.com/openshift/api/route/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -34,8 +40,9 @@ import (
 
 	set "github.com/deckarep/golang-set/v2"
 	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+	"github.com/stakater/ipshield-operator/pkg/backend"
+	"github.com/stakater/ipshield-operator/pkg/cidr"
 	corev1 "k8s.io/api/core/v1"
-	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -46,52 +53,94 @@ import (
 const (
 	IPShieldWatchedResourceLabel = "ipshield.stakater.cloud/enabled"
 	RouteAllowlistFinalizer      = "ipshield.stakater.cloud/finalizer"
-	AllowlistAnnotation          = "haproxy.router.openshift.io/ip_whitelist"
+	AllowlistAnnotation          = backend.AllowlistAnnotation
+	// IPAllowlistAnnotation supersedes AllowlistAnnotation on newer OpenShift releases
+	IPAllowlistAnnotation = backend.IPAllowlistAnnotation
 
 	DefaultWatchNamespace      = "ipshield-cr"
-	WatchedRoutesConfigMapName = "watched-routes"
+	WatchedRoutesConfigMapName = backend.WatchedRoutesConfigMapName
 )
 
+// allowlistObject is implemented by RouteAllowlist and ClusterRouteAllowlist, which share the reconciliation engine
+type allowlistObject interface {
+	client.Object
+	GetSpec() *networkingv1alpha1.RouteAllowlistSpec
+	GetStatus() *networkingv1alpha1.RouteAllowlistStatus
+}
+
 type RouteAllowlistReconciler struct {
 	client.Client
 	Scheme         *runtime.Scheme
 	WatchNamespace string
+	// RouteAnnotation is the annotation the allowlist is written to, AllowlistAnnotation if empty
+	RouteAnnotation string
+	// Backends enforce the allowlists on each kind of targets, the Route and Ingress backends if nil
+	Backends []backend.Backend
+	// Recorder records the events of the allowlists, events aren't recorded if nil
+	Recorder record.EventRecorder
+	// Clock is used to evaluate time-limited entries, the system clock if nil
+	Clock clock.PassiveClock
+	// AuthorizationPolicies enables the generation of Istio AuthorizationPolicies, it is only set when Istio is
+	// installed
+	AuthorizationPolicies bool
+	// ResyncPeriod is the interval at which allowlists are reconciled again, so drift is caught even if a
+	// watch event was missed. Disabled if zero.
+	ResyncPeriod time.Duration
 }
 
-func setCondition(conditions *[]metav1.Condition, conditionType, status, reason, message string) {
-	condition := metav1.Condition{
-		Type:    conditionType,
-		Status:  metav1.ConditionStatus(status),
-		Reason:  reason,
-		Message: message,
+func getEnv(key, defaultValue string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
 	}
-	apimeta.SetStatusCondition(conditions, condition)
+	return defaultValue
 }
 
-func setSuccessful(conditions *[]metav1.Condition, conditionType string) {
-	setCondition(conditions, conditionType, string(metav1.ConditionTrue), "ReconcileSuccessful", "Reconciliation successful")
+func GetWatchNamespace() string {
+	return getEnv("WATCH_NAMESPACE", DefaultWatchNamespace)
 }
 
-func setFailed(conditions *[]metav1.Condition, reconcileType string, err error) {
-	setCondition(conditions, reconcileType, string(metav1.ConditionFalse), "ReconcileError", fmt.Errorf("failed due to error %s", err).Error())
-}
+// patchIfChanged sends the patch only if it modifies the object. Empty patches on routes would otherwise
+// still be sent to the API server on every reconciliation.
+func (r *RouteAllowlistReconciler) patchIfChanged(ctx context.Context, obj client.Object, patch client.Patch) error {
+	changed, err := isChanged(obj, patch)
+	if err != nil || !changed {
+		return err
+	}
 
-func setWarning(conditions *[]metav1.Condition, reconcileType string, err error) {
-	setCondition(conditions, reconcileType, string(metav1.ConditionFalse), "ReconcileWarning", fmt.Errorf("an error occurred %s", err).Error())
+	return r.Patch(ctx, obj, patch)
 }
 
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
+func isChanged(obj client.Object, patch client.Patch) (bool, error) {
+	data, err := patch.Data(obj)
+	if err != nil {
+		return false, err
 	}
-	return defaultValue
+
+	fields := map[string]interface{}{}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return false, err
+	}
+	// Patches with an optimistic lock always carry the resource version
+	if metadata, ok := fields["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "resourceVersion")
+		if len(metadata) == 0 {
+			delete(fields, "metadata")
+		}
+	}
+	return len(fields) > 0, nil
 }
 
-func GetWatchNamespace() string {
-	return getEnv("WATCH_NAMESPACE", DefaultWatchNamespace)
+// getRoutePatch returns the patch of a target. RouteAllowlists and ClusterRouteAllowlists read-modify-write the same
+// contributions and allowlist, so the patch fails on conflict rather than dropping the ranges of another allowlist.
+func getRoutePatch(watchedRoute client.Object) client.Patch {
+	return client.MergeFromWithOptions(watchedRoute.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
 }
 
 func (r *RouteAllowlistReconciler) patchResourceAndStatus(ctx context.Context, obj client.Object, patch client.Patch, logger logr.Logger) error {
+	if changed, err := isChanged(obj, patch); err != nil || !changed {
+		return err
+	}
+
 	// Sending a deep copy because the object will be updated according to the remote server state
 	// so we need to keep the original object for the status update otherwise conditions will be lost
 	err := r.Status().Patch(ctx, obj.DeepCopyObject().(client.Object), patch)
@@ -108,13 +157,24 @@ func (r *RouteAllowlistReconciler) patchResourceAndStatus(ctx context.Context, o
 //+kubebuilder:rbac:groups=networking.stakater.com,resources=routeallowlists/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=networking.stakater.com,resources=routeallowlists/finalizers,verbs=update;patch
 //+kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=gateway.envoyproxy.io,resources=securitypolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=traefik.io,resources=ingressroutes,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=traefik.io,resources=middlewares,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=security.istio.io,resources=authorizationpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 
 func (r *RouteAllowlistReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return r.reconcile(ctx, req, &networkingv1alpha1.RouteAllowlist{})
+}
+
+// reconcile applies the allowlist read into cr, a RouteAllowlist or a ClusterRouteAllowlist, to the selected routes
+func (r *RouteAllowlistReconciler) reconcile(ctx context.Context, req ctrl.Request, cr allowlistObject) (ctrl.Result, error) {
 	logger := log.FromContext(ctx).WithName("ipShield-controller")
 	logger.Info("Reconciling IPShield")
 
-	cr := &networkingv1alpha1.RouteAllowlist{}
 	err := r.Get(ctx, req.NamespacedName, cr)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -122,108 +182,260 @@ func (r *RouteAllowlistReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 		return ctrl.Result{}, err
 	}
-	patchBase := client.MergeFrom(cr.DeepCopy())
+	patchBase := client.MergeFrom(cr.DeepCopyObject().(client.Object))
+	cr.GetStatus().ObservedGeneration = cr.GetGeneration()
 
-	apimeta.RemoveStatusCondition(&cr.Status.Conditions, "Admitted")
-	apimeta.RemoveStatusCondition(&cr.Status.Conditions, "Updating")
-	setCondition(&cr.Status.Conditions, "AllowlistReconciling", "True", "ProcessingAllowlist", "Searching for routes")
+	removeLegacyConditions(cr)
 
-	selector, err := metav1.LabelSelectorAsSelector(cr.Spec.LabelSelector)
+	selector, err := metav1.LabelSelectorAsSelector(cr.GetSpec().LabelSelector)
 	if err != nil {
 		logger.Error(err, "failed to parse label selector")
-		return ctrl.Result{}, err
+		setDegraded(cr, networkingv1alpha1.ReasonInvalidSpec, err)
+		return r.patchErrorStatus(ctx, cr, patchBase, err)
 	}
 
-	// Get routes
-	routes := &route.RouteList{}
+	namespaces, err := r.getSelectedNamespaces(ctx, cr)
+	if err != nil {
+		setDegraded(cr, networkingv1alpha1.ReasonNamespaceFetchFailed, err)
+		return r.patchErrorStatus(ctx, cr, patchBase, err)
+	}
 
-	err = r.List(ctx, routes, &client.ListOptions{
-		LabelSelector: selector,
-	})
+	// Routes and ingresses selected by the CR, along with those managed previously that are no longer selected
+	selections, err := r.getSelections(ctx, cr, selector, namespaces)
 
 	if err != nil {
-		setFailed(&cr.Status.Conditions, "RouteFetchError", err)
+		setDegraded(cr, networkingv1alpha1.ReasonRouteFetchFailed, err)
 		return r.patchErrorStatus(ctx, cr, patchBase, err)
-	} else {
-		apimeta.RemoveStatusCondition(&cr.Status.Conditions, "RouteFetchError")
 	}
 
 	// Handle delete
-	if cr.DeletionTimestamp != nil {
-		return r.handleDelete(ctx, routes, cr, patchBase, logger)
+	if cr.GetDeletionTimestamp() != nil {
+		return r.handleDelete(ctx, selections, cr, patchBase, logger)
 	} else {
 		controllerutil.AddFinalizer(cr, RouteAllowlistFinalizer)
 	}
 
-	if len(routes.Items) == 0 {
-		setSuccessful(&cr.Status.Conditions, "NoRoutesFound")
-		return ctrl.Result{}, r.patchResourceAndStatus(ctx, cr, patchBase, logger)
+	// Unsupported specs are reported once the deletion is handled, so they never prevent the CR from being deleted
+	if unsupported := r.getUnsupportedTargets(cr); len(unsupported) > 0 {
+		err = fmt.Errorf("no backend enforces allowlists on targets of kind %v", unsupported)
+		setDegraded(cr, networkingv1alpha1.ReasonInvalidSpec, err)
+		return r.patchErrorStatus(ctx, cr, patchBase, err)
+	}
+
+	if cr.GetSpec().AuthorizationPolicy != nil && !r.AuthorizationPolicies {
+		err = fmt.Errorf("spec.authorizationPolicy requires Istio AuthorizationPolicies to be served by the cluster")
+		setDegraded(cr, networkingv1alpha1.ReasonInvalidSpec, err)
+		return r.patchErrorStatus(ctx, cr, patchBase, err)
+	}
+
+	now := r.now()
+	r.updateEntryStatus(cr, now)
+
+	ipRanges, err := r.getIPRanges(ctx, cr, now)
+	if err != nil {
+		setDegraded(cr, networkingv1alpha1.ReasonIPSetFetchFailed, err)
+		return r.patchErrorStatus(ctx, cr, patchBase, err)
+	}
+
+	// Time-limited entries are applied or retracted at their next boundary
+	result := ctrl.Result{RequeueAfter: r.getRequeueAfter(getNextEntryTransition(cr.GetSpec().Entries, now))}
+
+	switch cr.GetSpec().Mode {
+	case networkingv1alpha1.ModePaused:
+		_, err = r.handlePaused(ctx, selections, cr, patchBase, logger)
+		return result, err
+	case networkingv1alpha1.ModeDryRun:
+		if _, err = r.handleDryRun(ctx, selections, ipRanges, cr, patchBase, logger); err != nil {
+			return ctrl.Result{}, err
+		}
+		return result, nil
+	}
+
+	if err = r.syncAuthorizationPolicies(ctx, cr, getSelectedRoutes(selections), ipRanges, logger); err != nil {
+		setDegraded(cr, networkingv1alpha1.ReasonAuthorizationPolicyUpdateFailed, err)
+		return r.patchErrorStatus(ctx, cr, patchBase, err)
+	}
+
+	if !slices.ContainsFunc(selections, selection.isManaged) {
+		updateRouteCounters(cr, 0)
+		setReady(cr, networkingv1alpha1.ReasonNoRoutesMatched, "No route matches the selectors")
+		return result, r.patchResourceAndStatus(ctx, cr, patchBase, logger)
 	}
 
-	return r.handleUpdate(ctx, routes, cr, patchBase, logger)
+	updateResult, err := r.handleUpdate(ctx, selections, ipRanges, cr, patchBase, logger)
+	if err != nil || updateResult.Requeue {
+		return updateResult, err
+	}
+	return result, nil
 }
 
-func (r *RouteAllowlistReconciler) handleUpdate(ctx context.Context, routes *route.RouteList, cr *networkingv1alpha1.RouteAllowlist, patch client.Patch, logger logr.Logger) (ctrl.Result, error) {
-	apimeta.RemoveStatusCondition(&cr.Status.Conditions, "ConfigMapUpdateFailure")
-	apimeta.RemoveStatusCondition(&cr.Status.Conditions, "RouteUpdateFailure")
+// getSelections returns the targets of each backend selected by the CR. Targets of the kinds the CR doesn't
+// target anymore are only part of the targets that are no longer selected.
+func (r *RouteAllowlistReconciler) getSelections(ctx context.Context, cr allowlistObject, selector labels.Selector,
+	namespaces set.Set[string]) ([]selection, error) {
+	var result []selection
+	for _, b := range r.getBackends() {
+		s := selection{backend: b}
 
-	configMap := &corev1.ConfigMap{}
-	err := r.getConfigMap(ctx, configMap, cr)
+		if isTargeted(cr, b.Kind()) {
+			selected, err := b.List(ctx, r.Client, selector)
+			if err != nil {
+				return nil, err
+			}
+			s.selected = filterByNamespace(selected, namespaces)
+		}
+
+		unselected, err := r.getUnselectedRoutes(ctx, b, s.selected, cr)
+		if err != nil {
+			return nil, err
+		}
+		s.unselected = unselected
+		result = append(result, s)
+	}
+	return result, nil
+}
 
+func (r *RouteAllowlistReconciler) handleUpdate(ctx context.Context, selections []selection, ipRanges []string,
+	cr allowlistObject, patch client.Patch, logger logr.Logger) (ctrl.Result, error) {
+	liveOwners, err := r.getLiveOwners(ctx)
 	if err != nil {
-		setWarning(&cr.Status.Conditions, "ConfigMapFetchFailure", fmt.Errorf("failed to get config map %e", err))
+		setDegraded(cr, networkingv1alpha1.ReasonAllowlistFetchFailed, err)
 		return r.patchErrorStatus(ctx, cr, patch, err)
 	}
 
-	for _, watchedRoute := range routes.Items {
-		routePatchBase := client.MergeFrom(watchedRoute.DeepCopy())
-		apimeta.RemoveStatusCondition(&cr.Status.Conditions, "Updating") // removing previous route condition
-		setCondition(&cr.Status.Conditions, "Updating", "True", "UpdatingRoute", fmt.Sprintf("Updating route '%s'", watchedRoute.Name))
+	for _, s := range selections {
+		if !s.isManaged() {
+			continue
+		}
+
+		if reason, err := r.updateSelection(ctx, s, ipRanges, cr, liveOwners, logger); err != nil {
+			if errors.IsConflict(err) {
+				return requeueOnConflict(err, logger)
+			}
+			setDegraded(cr, reason, err)
+			return r.patchErrorStatus(ctx, cr, patch, err)
+		}
+	}
+	setDriftCondition(cr)
+
+	matched := countSelected(selections)
+	if failed := updateRouteCounters(cr, matched); failed > 0 {
+		// Routes that were updated are kept in the status, so the other routes are only retried
+		err = fmt.Errorf("failed to apply the allowlist to %d route(s)", failed)
+		setDegraded(cr, networkingv1alpha1.ReasonRouteUpdateFailed, err)
+		if patchErr := r.patchResourceAndStatus(ctx, cr, patch, logger); patchErr != nil {
+			return ctrl.Result{}, patchErr
+		}
+		return ctrl.Result{}, err
+	}
+
+	if matched == 0 {
+		setReady(cr, networkingv1alpha1.ReasonNoRoutesMatched, "No route matches the selectors")
+	} else {
+		setReady(cr, networkingv1alpha1.ReasonApplied, fmt.Sprintf("Allowlist applied to %d route(s)", matched))
+	}
+
+	return ctrl.Result{}, r.patchResourceAndStatus(ctx, cr, patch, logger)
+}
 
-		if val, ok := watchedRoute.Labels[IPShieldWatchedResourceLabel]; !ok || val != "true" {
-			err = r.unwatchRoute(ctx, watchedRoute, client.MergeFrom(watchedRoute.DeepCopy()), cr, configMap, logger)
+// updateSelection applies the allowlist to the selected targets of a backend and restores those that are no longer
+// selected. Errors preventing the other targets from being updated are returned with the reason to report.
+func (r *RouteAllowlistReconciler) updateSelection(ctx context.Context, s selection, ipRanges []string, cr allowlistObject,
+	liveOwners liveOwners, logger logr.Logger) (string, error) {
+	configMap := &corev1.ConfigMap{}
+	err := r.getConfigMap(ctx, configMap, s.backend.ConfigMapName(), cr)
+
+	if err != nil {
+		return networkingv1alpha1.ReasonConfigMapUpdateFailed, fmt.Errorf("failed to get config map: %w", err)
+	}
+
+	for _, unselectedRoute := range s.unselected {
+		err = r.unwatchRoute(ctx, s.backend, unselectedRoute, getRoutePatch(unselectedRoute), cr, configMap, liveOwners, logger)
+
+		if err != nil {
+			logger.Error(err, "failed to unwatch route that is no longer selected")
+			return networkingv1alpha1.ReasonRouteRestoreFailed, err
+		}
+		removeRouteStatus(cr, s.backend, unselectedRoute)
+	}
+
+	for _, watchedRoute := range s.selected {
+		routePatchBase := getRoutePatch(watchedRoute)
+
+		if val, ok := watchedRoute.GetLabels()[IPShieldWatchedResourceLabel]; !ok || val != "true" {
+			err = r.unwatchRoute(ctx, s.backend, watchedRoute, routePatchBase, cr, configMap, liveOwners, logger)
 
 			if err != nil {
-				apimeta.RemoveStatusCondition(&cr.Status.Conditions, "Updating")
-				setFailed(&cr.Status.Conditions, "RouteUpdateFailure", err)
 				logger.Error(err, "failed to unwatch route")
-				return r.patchErrorStatus(ctx, cr, patch, err)
+				return networkingv1alpha1.ReasonRouteRestoreFailed, err
 			}
+			removeRouteStatus(cr, s.backend, watchedRoute)
 			continue
 		}
 
-		if err = r.updateConfigMap(ctx, watchedRoute, cr, configMap); err != nil {
-			return ctrl.Result{}, err
+		annotations := getAnnotations(watchedRoute)
+		previous, err := getContributions(annotations)
+		var current []string
+		if err == nil {
+			current, err = s.backend.GetAllowlist(ctx, r.Client, watchedRoute)
+		}
+		if err != nil {
+			// The route is skipped, its allowlist can't be updated without knowing the ranges of the other allowlists
+			logger.Error(err, "failed to read route allowlist", "kind", s.backend.Kind(), "route", client.ObjectKeyFromObject(watchedRoute))
+			r.setRouteStatus(cr, s.backend, watchedRoute, "", false, err)
+			continue
+		}
+
+		if err = r.updateConfigMap(ctx, watchedRoute, current, previous, configMap); err != nil {
+			return networkingv1alpha1.ReasonConfigMapUpdateFailed, err
 		}
 
-		if watchedRoute.Annotations == nil {
-			watchedRoute.Annotations = make(map[string]string)
+		if err = r.updateEntryRecords(ctx, watchedRoute, cr, configMap, liveOwners); err != nil {
+			return networkingv1alpha1.ReasonConfigMapUpdateFailed, err
 		}
 
-		watchedRoute.Annotations[AllowlistAnnotation] = mergeSet(strings.Split(watchedRoute.Annotations[AllowlistAnnotation], " "), cr.Spec.IPRanges)
+		delete(annotations, OriginalAllowlistAnnotation)
+
+		next := previous.live(liveOwners, s.backend.Kind(), watchedRoute).with(getOwnerKey(cr), ipRanges, cr.GetSpec().Strategy)
+		allowlist, drift := getNextAllowlist(cr.GetSpec().DriftPolicy, current, configMap.Data[getRouteFullName(watchedRoute)], previous, next)
 
-		err = r.Patch(ctx, &watchedRoute, routePatchBase)
+		if err = setContributions(annotations, next); err != nil {
+			return networkingv1alpha1.ReasonRouteUpdateFailed, err
+		}
+		// Unstructured targets return a copy of their annotations
+		watchedRoute.SetAnnotations(annotations)
+
+		err = s.backend.Apply(ctx, r.Client, cr, watchedRoute, strings.Fields(allowlist))
+		changed := false
+		if err == nil {
+			changed, err = isChanged(watchedRoute, routePatchBase)
+		}
+		if err == nil && changed {
+			err = r.Patch(ctx, watchedRoute, routePatchBase)
+		}
 
+		if errors.IsConflict(err) {
+			return networkingv1alpha1.ReasonRouteUpdateFailed, err
+		}
 		if err != nil {
-			apimeta.RemoveStatusCondition(&cr.Status.Conditions, "Updating")
-			setFailed(&cr.Status.Conditions, "RouteUpdateFailure", err)
-			logger.Error(err, "failed to update route")
-			return r.patchErrorStatus(ctx, cr, patch, err)
+			logger.Error(err, "failed to update route", "kind", s.backend.Kind(), "route", client.ObjectKeyFromObject(watchedRoute))
 		}
-	}
 
-	apimeta.RemoveStatusCondition(&cr.Status.Conditions, "Updating")
-	apimeta.RemoveStatusCondition(&cr.Status.Conditions, "AllowlistReconciling")
-	setSuccessful(&cr.Status.Conditions, "Admitted")
+		var previousDrift *networkingv1alpha1.AllowlistChanges
+		if routeStatus := getRouteStatus(cr, s.backend, watchedRoute); routeStatus != nil {
+			previousDrift = routeStatus.Drift
+		}
+		r.setRouteStatus(cr, s.backend, watchedRoute, allowlist, changed, err)
+		r.reportDrift(cr, s.backend, watchedRoute, previousDrift, drift)
+	}
 
-	return ctrl.Result{}, r.patchResourceAndStatus(ctx, cr, patch, logger)
+	return "", nil
 }
 
-func (r *RouteAllowlistReconciler) updateConfigMap(ctx context.Context, watchedRoute route.Route, cr *networkingv1alpha1.RouteAllowlist, configMap *corev1.ConfigMap) error {
-	apimeta.RemoveStatusCondition(&cr.Status.Conditions, "ConfigMapUpdateFailure")
-
+func (r *RouteAllowlistReconciler) updateConfigMap(ctx context.Context, watchedRoute client.Object, current []string,
+	previous contributions, configMap *corev1.ConfigMap) error {
 	patchBase := client.MergeFrom(configMap.DeepCopy())
-	routeFullName := fmt.Sprintf("%s__%s", watchedRoute.Namespace, watchedRoute.Name)
+	routeFullName := getRouteFullName(watchedRoute)
 
 	if _, ok := configMap.Data[routeFullName]; ok {
 		return nil
@@ -233,52 +445,84 @@ func (r *RouteAllowlistReconciler) updateConfigMap(ctx context.Context, watchedR
 		configMap.Data = make(map[string]string)
 	}
 
-	original := watchedRoute.Annotations[AllowlistAnnotation]
+	// Ranges already contributed by other RouteAllowlists are not part of the original value, unless it was
+	// recorded when the allowlists were applied at admission
+	original, ok := watchedRoute.GetAnnotations()[OriginalAllowlistAnnotation]
+	if !ok {
+		original = diffSet(current, previous.ranges())
+	}
 	configMap.Data[routeFullName] = original
 
-	return r.Patch(ctx, configMap, patchBase)
+	return r.patchIfChanged(ctx, configMap, patchBase)
 }
 
-func (r *RouteAllowlistReconciler) handleDelete(ctx context.Context, routes *route.RouteList, cr *networkingv1alpha1.RouteAllowlist, patch client.Patch, logger logr.Logger) (ctrl.Result, error) {
-	apimeta.RemoveStatusCondition(&cr.Status.Conditions, "RouteDeleteFailure")
-
-	configMap := &corev1.ConfigMap{}
-	err := r.getConfigMap(ctx, configMap, cr)
-
+func (r *RouteAllowlistReconciler) handleDelete(ctx context.Context, selections []selection, cr allowlistObject, patch client.Patch, logger logr.Logger) (ctrl.Result, error) {
+	liveOwners, err := r.getLiveOwners(ctx)
 	if err != nil {
-		setWarning(&cr.Status.Conditions, "ConfigMapFetchFailure", fmt.Errorf("failed to get config map %s", err))
+		setDegraded(cr, networkingv1alpha1.ReasonAllowlistFetchFailed, err)
 		return r.patchErrorStatus(ctx, cr, patch, err)
-	} else {
-		apimeta.RemoveStatusCondition(&cr.Status.Conditions, "ConfigMapFetchFailure")
 	}
 
-	for _, watchedRoute := range routes.Items {
-		routePatch := client.MergeFrom(watchedRoute.DeepCopy())
-		if err = r.unwatchRoute(ctx, watchedRoute, routePatch, cr, configMap, logger); err != nil {
-			setFailed(&cr.Status.Conditions, "RouteDeleteFailure", err)
-			return r.patchErrorStatus(ctx, cr, patch, err)
+	for _, s := range selections {
+		configMap := &corev1.ConfigMap{}
+		if s.isManaged() {
+			err = r.getConfigMap(ctx, configMap, s.backend.ConfigMapName(), cr)
 		} else {
-			apimeta.RemoveStatusCondition(&cr.Status.Conditions, "RouteDeleteFailure")
+			// The config map is only released, it isn't created for backends the CR never managed
+			err = r.Get(ctx, types.NamespacedName{Name: s.backend.ConfigMapName(), Namespace: r.WatchNamespace}, configMap)
+			if errors.IsNotFound(err) {
+				continue
+			}
 		}
-	}
 
-	setSuccessful(&cr.Status.Conditions, "Deleted")
-	controllerutil.RemoveFinalizer(cr, RouteAllowlistFinalizer)
+		if err != nil {
+			setDegraded(cr, networkingv1alpha1.ReasonConfigMapUpdateFailed, fmt.Errorf("failed to get config map: %w", err))
+			return r.patchErrorStatus(ctx, cr, patch, err)
+		}
 
-	cfgPatch := client.MergeFrom(configMap.DeepCopy())
-	err = controllerutil.RemoveOwnerReference(cr, configMap, r.Scheme)
-	if err != nil {
-		return ctrl.Result{}, err
+		for _, watchedRoute := range slices.Concat(s.selected, s.unselected) {
+			routePatch := getRoutePatch(watchedRoute)
+			if err = r.unwatchRoute(ctx, s.backend, watchedRoute, routePatch, cr, configMap, liveOwners, logger); err != nil {
+				if errors.IsConflict(err) {
+					return requeueOnConflict(err, logger)
+				}
+				setDegraded(cr, networkingv1alpha1.ReasonRouteRestoreFailed, err)
+				return r.patchErrorStatus(ctx, cr, patch, err)
+			}
+			removeRouteStatus(cr, s.backend, watchedRoute)
+		}
+
+		if err = r.removeOwnerReferenceIfExists(ctx, configMap, cr); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
-	if err = r.Patch(ctx, configMap, cfgPatch); err != nil {
-		return ctrl.Result{}, err
+
+	if err = r.syncAuthorizationPolicies(ctx, cr, nil, nil, logger); err != nil {
+		setDegraded(cr, networkingv1alpha1.ReasonAuthorizationPolicyUpdateFailed, err)
+		return r.patchErrorStatus(ctx, cr, patch, err)
 	}
 
+	controllerutil.RemoveFinalizer(cr, RouteAllowlistFinalizer)
+
 	return ctrl.Result{}, r.patchResourceAndStatus(ctx, cr, patch, logger)
 
 }
 
-func (r *RouteAllowlistReconciler) patchErrorStatus(ctx context.Context, cr *networkingv1alpha1.RouteAllowlist, patch client.Patch, err error) (ctrl.Result, error) {
+// recordEvent records an event on the object, if the reconciler has a recorder
+func (r *RouteAllowlistReconciler) recordEvent(obj runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+	}
+}
+
+// requeueOnConflict reconciles the CR again when a route was modified concurrently, e.g. by the reconciler of another
+// allowlist, the route is read again rather than reported as failed
+func requeueOnConflict(err error, logger logr.Logger) (ctrl.Result, error) {
+	logger.Info("Route modified concurrently, requeuing", "reason", err.Error())
+	return ctrl.Result{Requeue: true}, nil
+}
+
+func (r *RouteAllowlistReconciler) patchErrorStatus(ctx context.Context, cr allowlistObject, patch client.Patch, err error) (ctrl.Result, error) {
 	patchErr := r.Status().Patch(ctx, cr, patch)
 
 	if patchErr != nil {
@@ -287,62 +531,287 @@ func (r *RouteAllowlistReconciler) patchErrorStatus(ctx context.Context, cr *net
 	return ctrl.Result{}, err
 }
 
-func (r *RouteAllowlistReconciler) unwatchRoute(ctx context.Context, watchedRoute route.Route, routePatch client.Patch,
-	cr *networkingv1alpha1.RouteAllowlist, configMap *corev1.ConfigMap, logger logr.Logger) error {
+// unwatchRoute withdraws the ranges contributed by the CR from the target. Once no contribution is left the
+// original allowlist stored in the config map is restored, dropping the ranges added outside of IPShield.
+func (r *RouteAllowlistReconciler) unwatchRoute(ctx context.Context, b backend.Backend, watchedRoute client.Object, routePatch client.Patch,
+	cr allowlistObject, configMap *corev1.ConfigMap, liveOwners liveOwners, logger logr.Logger) error {
 
-	routeFullName := fmt.Sprintf("%s__%s", watchedRoute.Namespace, watchedRoute.Name)
-
-	configMapPatch := client.MergeFrom(configMap.DeepCopy())
+	routeFullName := getRouteFullName(watchedRoute)
 
-	diff := diffSet(strings.Split(watchedRoute.Annotations[AllowlistAnnotation], " "), cr.Spec.IPRanges)
-	configMapValues := configMap.Data[routeFullName]
+	annotations := getAnnotations(watchedRoute)
+	previous, err := getContributions(annotations)
+	if err != nil {
+		return err
+	}
 
-	if diff == "" {
-		diff = configMapValues
+	configMapValues, ok := configMap.Data[routeFullName]
+	if len(previous) == 0 && !ok {
+		// Route was never managed by IPShield
+		return nil
 	}
 
-	if diffSet(strings.Split(diff, " "), strings.Split(configMapValues, " ")) == "" {
+	configMapPatch := client.MergeFrom(configMap.DeepCopy())
+
+	next := previous.live(liveOwners, b.Kind(), watchedRoute).without(getOwnerKey(cr))
+	if len(next) == 0 {
 		delete(configMap.Data, routeFullName)
 	}
 
-	if diff == "" {
-		delete(watchedRoute.Annotations, AllowlistAnnotation)
+	if err = setEntryRecords(configMap.Data, getEntriesKey(watchedRoute), getOwnerKey(cr), nil, liveOwners); err != nil {
+		return err
+	}
+
+	if len(next) == 0 {
+		err = b.Restore(ctx, r.Client, cr, watchedRoute, strings.Fields(computeAllowlist(configMapValues, nil)))
 	} else {
-		if watchedRoute.Annotations == nil {
-			watchedRoute.Annotations = make(map[string]string)
+		// Only the ranges of the CR are retracted, the drift is handled following the remaining allowlists
+		var current []string
+		current, err = b.GetAllowlist(ctx, r.Client, watchedRoute)
+		if err == nil {
+			allowlist, _ := getNextAllowlist(getRemainingDriftPolicy(liveOwners, next), current, configMapValues, previous, next)
+			err = b.Apply(ctx, r.Client, cr, watchedRoute, strings.Fields(allowlist))
 		}
-		watchedRoute.Annotations[AllowlistAnnotation] = diff
 	}
+	if err != nil {
+		return err
+	}
+
+	if err = setContributions(annotations, next); err != nil {
+		return err
+	}
+	watchedRoute.SetAnnotations(annotations)
 
-	err := r.Patch(ctx, configMap, configMapPatch)
+	err = r.patchIfChanged(ctx, configMap, configMapPatch)
 	if err != nil {
 		logger.Error(err, "failed to update config map")
 		return err
 	}
 
-	return r.Patch(ctx, &watchedRoute, routePatch)
+	return r.patchIfChanged(ctx, watchedRoute, routePatch)
 }
 
-func (r *RouteAllowlistReconciler) getConfigMap(ctx context.Context, configMap *corev1.ConfigMap, cr *networkingv1alpha1.RouteAllowlist) error {
-	err := r.Get(ctx, types.NamespacedName{Name: WatchedRoutesConfigMapName, Namespace: r.WatchNamespace}, configMap)
+// getUnselectedRoutes returns the targets of the backend managed by the CR that are not part of the selected targets anymore
+func (r *RouteAllowlistReconciler) getUnselectedRoutes(ctx context.Context, b backend.Backend, selectedRoutes []client.Object,
+	cr allowlistObject) ([]client.Object, error) {
+	selected := set.NewSet[types.NamespacedName]()
+	for _, item := range selectedRoutes {
+		selected.Add(client.ObjectKeyFromObject(item))
+	}
+
+	var result []client.Object
+	for _, routeStatus := range slices.Clone(cr.GetStatus().Routes) {
+		key := types.NamespacedName{Namespace: routeStatus.Namespace, Name: routeStatus.Name}
+		if getStatusKind(routeStatus) != b.Kind() || selected.Contains(key) {
+			continue
+		}
+
+		unselectedRoute := b.NewObject()
+		err := r.Get(ctx, key, unselectedRoute)
+
+		if errors.IsNotFound(err) {
+			unselectedRoute.SetNamespace(key.Namespace)
+			unselectedRoute.SetName(key.Name)
+			removeRouteStatus(cr, b, unselectedRoute)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, unselectedRoute)
+	}
+
+	return result, nil
+}
+
+// setRouteStatus records the allowlist applied to the target, or the error preventing it from being applied.
+// The last applied time is only moved when the target was updated.
+func (r *RouteAllowlistReconciler) setRouteStatus(cr allowlistObject, b backend.Backend, watchedRoute client.Object, allowlist string,
+	updated bool, err error) {
+	routeStatus := networkingv1alpha1.RouteStatus{
+		Kind:      b.Kind(),
+		Namespace: watchedRoute.GetNamespace(),
+		Name:      watchedRoute.GetName(),
+		Host:      b.GetHost(watchedRoute),
+		Allowlist: allowlist,
+	}
+
+	if previous := getRouteStatus(cr, b, watchedRoute); previous != nil {
+		routeStatus.LastAppliedTime = previous.LastAppliedTime
+	}
+
+	if err != nil {
+		routeStatus.Error = err.Error()
+	} else if updated || routeStatus.LastAppliedTime == nil {
+		routeStatus.LastAppliedTime = &metav1.Time{Time: r.now()}
+	}
+
+	putRouteStatus(cr, routeStatus)
+}
+
+// getStatusKind returns the kind of the object reported in the status, routes were reported without a kind
+// before ingresses were supported
+func getStatusKind(routeStatus networkingv1alpha1.RouteStatus) networkingv1alpha1.TargetKind {
+	if routeStatus.Kind == "" {
+		return networkingv1alpha1.TargetRoute
+	}
+	return routeStatus.Kind
+}
+
+func isStatusOf(routeStatus networkingv1alpha1.RouteStatus, kind networkingv1alpha1.TargetKind, namespace, name string) bool {
+	return getStatusKind(routeStatus) == kind && routeStatus.Namespace == namespace && routeStatus.Name == name
+}
+
+// getRouteStatus returns the status of the target, nil if the target isn't part of the status
+func getRouteStatus(cr allowlistObject, b backend.Backend, watchedRoute client.Object) *networkingv1alpha1.RouteStatus {
+	status := cr.GetStatus()
+	i := slices.IndexFunc(status.Routes, func(item networkingv1alpha1.RouteStatus) bool {
+		return isStatusOf(item, b.Kind(), watchedRoute.GetNamespace(), watchedRoute.GetName())
+	})
+	if i < 0 {
+		return nil
+	}
+	return &status.Routes[i]
+}
+
+// putRouteStatus replaces the status of the target, or inserts it keeping the targets sorted
+func putRouteStatus(cr allowlistObject, routeStatus networkingv1alpha1.RouteStatus) {
+	status := cr.GetStatus()
+	i := slices.IndexFunc(status.Routes, func(item networkingv1alpha1.RouteStatus) bool {
+		return isStatusOf(item, getStatusKind(routeStatus), routeStatus.Namespace, routeStatus.Name)
+	})
+	if i >= 0 {
+		status.Routes[i] = routeStatus
+		return
+	}
+
+	status.Routes = append(status.Routes, routeStatus)
+	slices.SortFunc(status.Routes, func(a, b networkingv1alpha1.RouteStatus) int {
+		return cmp.Or(strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name),
+			strings.Compare(string(getStatusKind(a)), string(getStatusKind(b))))
+	})
+}
+
+func removeRouteStatus(cr allowlistObject, b backend.Backend, watchedRoute client.Object) {
+	status := cr.GetStatus()
+	status.Routes = slices.DeleteFunc(status.Routes, func(item networkingv1alpha1.RouteStatus) bool {
+		return isStatusOf(item, b.Kind(), watchedRoute.GetNamespace(), watchedRoute.GetName())
+	})
+}
+
+// updateRouteCounters updates the route counters of the status and returns the number of failed routes.
+// Routes with pending changes in DryRun mode are neither applied nor failed.
+func updateRouteCounters(cr allowlistObject, matched int) int32 {
+	status := cr.GetStatus()
+	status.MatchedRoutes = int32(matched)
+	status.AppliedRoutes = 0
+	status.FailedRoutes = 0
+
+	for _, routeStatus := range status.Routes {
+		switch {
+		case routeStatus.Error != "":
+			status.FailedRoutes++
+		case routeStatus.PendingChanges == nil:
+			status.AppliedRoutes++
+		}
+	}
+	return status.FailedRoutes
+}
+
+// liveOwner holds the selection of a RouteAllowlist or ClusterRouteAllowlist whose contributions are still valid
+type liveOwner struct {
+	cr         allowlistObject
+	selector   labels.Selector
+	namespaces set.Set[string]
+}
+
+// liveOwners maps the keys of the RouteAllowlists and ClusterRouteAllowlists whose contributions are still valid
+// to their selection
+type liveOwners map[string]liveOwner
+
+// contains reports whether the allowlist of the key exists
+func (o liveOwners) contains(key string) bool {
+	_, ok := o[key]
+	return ok
+}
+
+// selects reports whether the allowlist of the key exists and selects the target of the given kind
+func (o liveOwners) selects(key string, kind networkingv1alpha1.TargetKind, target client.Object) bool {
+	owner, ok := o[key]
+	if !ok || !isTargeted(owner.cr, kind) || !owner.selector.Matches(labels.Set(target.GetLabels())) {
+		return false
+	}
+	return owner.namespaces == nil || owner.namespaces.Contains(target.GetNamespace())
+}
+
+// getLiveOwners returns the RouteAllowlists and ClusterRouteAllowlists whose contributions are still valid
+func (r *RouteAllowlistReconciler) getLiveOwners(ctx context.Context) (liveOwners, error) {
+	allowlists := &networkingv1alpha1.RouteAllowlistList{}
+	if err := r.List(ctx, allowlists, client.InNamespace(r.WatchNamespace)); err != nil {
+		return nil, err
+	}
+
+	clusterAllowlists := &networkingv1alpha1.ClusterRouteAllowlistList{}
+	if err := r.List(ctx, clusterAllowlists); err != nil {
+		return nil, err
+	}
+
+	var crs []allowlistObject
+	for i := range allowlists.Items {
+		crs = append(crs, &allowlists.Items[i])
+	}
+	for i := range clusterAllowlists.Items {
+		crs = append(crs, &clusterAllowlists.Items[i])
+	}
+
+	owners := make(liveOwners, len(crs))
+	for _, cr := range crs {
+		if cr.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(cr.GetSpec().LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+		namespaces, err := r.getSelectedNamespaces(ctx, cr)
+		if err != nil {
+			return nil, err
+		}
+		owners[getOwnerKey(cr)] = liveOwner{cr: cr, selector: selector, namespaces: namespaces}
+	}
+	return owners, nil
+}
+
+// getOwnerKey returns the key identifying the contributions of the CR on routes, namespace/name for
+// RouteAllowlists and name for ClusterRouteAllowlists
+func getOwnerKey(cr client.Object) string {
+	if cr.GetNamespace() == "" {
+		return cr.GetName()
+	}
+	return client.ObjectKeyFromObject(cr).String()
+}
+
+// getConfigMap returns the config map holding the original allowlists of a kind, it is created if missing
+func (r *RouteAllowlistReconciler) getConfigMap(ctx context.Context, configMap *corev1.ConfigMap, name string, cr allowlistObject) error {
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: r.WatchNamespace}, configMap)
 	if err == nil {
 		return r.setOwnerReferenceIfNotExists(ctx, configMap, cr)
 	}
 
 	if errors.IsNotFound(err) {
-		err = r.createConfigMap(ctx, cr)
+		err = r.createConfigMap(ctx, name, cr)
 		if err == nil {
-			err = r.Get(ctx, types.NamespacedName{Name: WatchedRoutesConfigMapName, Namespace: r.WatchNamespace}, configMap)
+			err = r.Get(ctx, types.NamespacedName{Name: name, Namespace: r.WatchNamespace}, configMap)
 		}
 	}
 
 	return err
 }
 
-func (r *RouteAllowlistReconciler) createConfigMap(ctx context.Context, cr *networkingv1alpha1.RouteAllowlist) error {
+func (r *RouteAllowlistReconciler) createConfigMap(ctx context.Context, name string, cr allowlistObject) error {
 	configMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      WatchedRoutesConfigMapName,
+			Name:      name,
 			Namespace: r.WatchNamespace,
 		},
 	}
@@ -355,7 +824,7 @@ func (r *RouteAllowlistReconciler) createConfigMap(ctx context.Context, cr *netw
 	return r.Create(ctx, configMap)
 }
 
-func (r *RouteAllowlistReconciler) setOwnerReferenceIfNotExists(ctx context.Context, configMap *corev1.ConfigMap, cr *networkingv1alpha1.RouteAllowlist) error {
+func (r *RouteAllowlistReconciler) setOwnerReferenceIfNotExists(ctx context.Context, configMap *corev1.ConfigMap, cr allowlistObject) error {
 	ok, err := controllerutil.HasOwnerReference(configMap.OwnerReferences, cr, r.Scheme)
 	if err == nil && !ok {
 		patchBase := client.MergeFrom(configMap.DeepCopy())
@@ -367,26 +836,46 @@ func (r *RouteAllowlistReconciler) setOwnerReferenceIfNotExists(ctx context.Cont
 	return err
 }
 
-func mergeSet(s1 []string, s2 []string) string {
-	merged := set.NewSet(s1...).Union(set.NewSet(s2...))
-	return setToIPString(merged)
+func (r *RouteAllowlistReconciler) removeOwnerReferenceIfExists(ctx context.Context, configMap *corev1.ConfigMap, cr allowlistObject) error {
+	ok, err := controllerutil.HasOwnerReference(configMap.OwnerReferences, cr, r.Scheme)
+	if err == nil && ok {
+		patchBase := client.MergeFrom(configMap.DeepCopy())
+		err = controllerutil.RemoveOwnerReference(cr, configMap, r.Scheme)
+		if err == nil {
+			return r.Patch(ctx, configMap, patchBase)
+		}
+	}
+	return err
 }
 
-func diffSet(s1 []string, s2 []string) string {
-	diff := set.NewSet(s1...).Difference(set.NewSet(s2...))
-	return setToIPString(diff)
+// getRouteFullName returns the key under which the original allowlist of the target is stored in the config map
+// of its backend
+func getRouteFullName(watchedRoute client.Object) string {
+	return fmt.Sprintf("%s__%s", watchedRoute.GetNamespace(), watchedRoute.GetName())
 }
 
-func setToIPString(s set.Set[string]) string {
-	s.Remove("")
-	return strings.Join(s.ToSlice(), " ")
+func (r *RouteAllowlistReconciler) getRouteAnnotation() string {
+	if r.RouteAnnotation == "" {
+		return AllowlistAnnotation
+	}
+	return r.RouteAnnotation
 }
 
-func (r *RouteAllowlistReconciler) mapRouteToRouteAllowlist(ctx context.Context, obj client.Object) []reconcile.Request {
-	logger := log.FromContext(ctx).WithName("mapRouteToRouteAllowlist")
-	openshiftRoute := obj.(*route.Route)
+// mergeSet returns the canonical union of both lists of ranges
+func mergeSet(s1 []string, s2 []string) string {
+	return strings.Join(cidr.Merge(s1, s2), " ")
+}
 
-	if val, ok := openshiftRoute.Labels[IPShieldWatchedResourceLabel]; !ok || val != "true" {
+// diffSet returns the canonical list of ranges of s1 that are not covered by s2
+func diffSet(s1 []string, s2 []string) string {
+	return strings.Join(cidr.Subtract(s1, s2), " ")
+}
+
+// mapTargetToRouteAllowlist enqueues the RouteAllowlists when an enabled target changes
+func (r *RouteAllowlistReconciler) mapTargetToRouteAllowlist(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx).WithName("mapTargetToRouteAllowlist")
+
+	if val, ok := obj.GetLabels()[IPShieldWatchedResourceLabel]; !ok || val != "true" {
 		return nil
 	}
 
@@ -415,12 +904,24 @@ func (r *RouteAllowlistReconciler) mapRouteToRouteAllowlist(ctx context.Context,
 	return result
 }
 
+// watchTargets watches for label and annotation changes of the targets of every backend
+func (r *RouteAllowlistReconciler) watchTargets(bldr *builder.Builder, mapFunc handler.MapFunc) *builder.Builder {
+	for _, b := range r.getBackends() {
+		bldr = bldr.Watches(b.NewObject(), handler.EnqueueRequestsFromMapFunc(mapFunc),
+			builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})))
+	}
+	return bldr
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *RouteAllowlistReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&networkingv1alpha1.RouteAllowlist{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
-		// Watch for route labels and annotations changes
-		Watches(&route.Route{}, handler.EnqueueRequestsFromMapFunc(r.mapRouteToRouteAllowlist),
-			builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+	return r.watchTargets(ctrl.NewControllerManagedBy(mgr).
+		For(&networkingv1alpha1.RouteAllowlist{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})), r.mapTargetToRouteAllowlist).
+		// Reconcile the allowlists referencing an IPSet when its ranges change
+		Watches(&networkingv1alpha1.IPSet{}, handler.EnqueueRequestsFromMapFunc(r.mapIPSetToRouteAllowlist),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Namespaces entering or leaving a namespace selector change the selected routes
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.mapNamespaceToRouteAllowlist),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
 		Complete(r)
 }
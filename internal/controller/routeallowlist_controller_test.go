@@ -20,21 +20,34 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
 	v1 "github.com/openshift/api/route/v1"
 	"github.com/stakater/ipshield-operator/test/utils"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	scheme2 "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+	"github.com/stakater/ipshield-operator/pkg/backend"
 )
 
 var _ = Describe("RouteAllowlist Controller", Ordered, func() {
@@ -45,13 +58,17 @@ var _ = Describe("RouteAllowlist Controller", Ordered, func() {
 		allowlist  *networkingv1alpha1.RouteAllowlist
 		r          *unstructured.Unstructured
 		fakeClient client.Client
+		scheme     *runtime.Scheme
 	)
 
 	ctx = context.Background()
 
 	BeforeEach(func() {
 		ctx = context.Background()
-		scheme := scheme2.Scheme
+		// The kinds of unstructured targets are registered by the tests, so they get a scheme of their own
+		scheme = runtime.NewScheme()
+		Expect(scheme2.AddToScheme(scheme)).To(Succeed())
+		Expect(v1.AddToScheme(scheme)).To(Succeed())
 		Expect(networkingv1alpha1.AddToScheme(scheme)).To(Succeed())
 
 		r = &unstructured.Unstructured{}
@@ -93,13 +110,14 @@ var _ = Describe("RouteAllowlist Controller", Ordered, func() {
 		fakeClient = fakeclient.NewClientBuilder().
 			WithScheme(scheme).
 			WithObjects(r, allowlist, configMap).
-			WithStatusSubresource(r, allowlist, configMap).
+			WithStatusSubresource(r, allowlist, configMap, &networkingv1alpha1.ClusterRouteAllowlist{}).
 			Build()
 
 		reconciler = &RouteAllowlistReconciler{
 			Client:         fakeClient,
 			Scheme:         scheme,
 			WatchNamespace: DefaultWatchNamespace,
+			Recorder:       record.NewFakeRecorder(10),
 		}
 	})
 
@@ -124,9 +142,13 @@ var _ = Describe("RouteAllowlist Controller", Ordered, func() {
 		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24"))
 
 		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: allowlist.Namespace, Name: allowlist.Name}, allowlist)).Should(Succeed())
-		Expect(allowlist.Status.Conditions).To(HaveLen(1))
+		Expect(allowlist.Status.Conditions).To(HaveLen(3))
 
-		Expect(allowlist.Status.Conditions[0].Type).Should(Equal("Admitted"))
+		Expect(apimeta.FindStatusCondition(allowlist.Status.Conditions, networkingv1alpha1.ConditionReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status":             Equal(metav1.ConditionTrue),
+			"Reason":             Equal(networkingv1alpha1.ReasonApplied),
+			"ObservedGeneration": Equal(allowlist.Generation),
+		})))
 
 		watchedRoutes := &corev1.ConfigMap{}
 		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: DefaultWatchNamespace, Name: WatchedRoutesConfigMapName}, watchedRoutes)).Should(Succeed())
@@ -159,9 +181,13 @@ var _ = Describe("RouteAllowlist Controller", Ordered, func() {
 		Expect(strings.Split(osRoute.Annotations[AllowlistAnnotation], " ")).Should(ConsistOf([]string{"10.100.123.24", "10.33.52.5"}))
 
 		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: allowlist.Namespace, Name: allowlist.Name}, allowlist)).Should(Succeed())
-		Expect(allowlist.Status.Conditions).To(HaveLen(1))
+		Expect(allowlist.Status.Conditions).To(HaveLen(3))
 
-		Expect(allowlist.Status.Conditions[0].Type).Should(Equal("Admitted"))
+		Expect(apimeta.FindStatusCondition(allowlist.Status.Conditions, networkingv1alpha1.ConditionReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status":             Equal(metav1.ConditionTrue),
+			"Reason":             Equal(networkingv1alpha1.ReasonApplied),
+			"ObservedGeneration": Equal(allowlist.Generation),
+		})))
 
 		watchedRoutes := &corev1.ConfigMap{}
 		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: DefaultWatchNamespace, Name: WatchedRoutesConfigMapName}, watchedRoutes)).Should(Succeed())
@@ -191,9 +217,13 @@ var _ = Describe("RouteAllowlist Controller", Ordered, func() {
 		Expect(strings.Split(osRoute.Annotations[AllowlistAnnotation], " ")).Should(ConsistOf([]string{"10.100.123.24", "10.33.52.5"}))
 
 		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: allowlist.Namespace, Name: allowlist.Name}, allowlist)).Should(Succeed())
-		Expect(allowlist.Status.Conditions).To(HaveLen(1))
+		Expect(allowlist.Status.Conditions).To(HaveLen(3))
 
-		Expect(allowlist.Status.Conditions[0].Type).Should(Equal("Admitted"))
+		Expect(apimeta.FindStatusCondition(allowlist.Status.Conditions, networkingv1alpha1.ConditionReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status":             Equal(metav1.ConditionTrue),
+			"Reason":             Equal(networkingv1alpha1.ReasonApplied),
+			"ObservedGeneration": Equal(allowlist.Generation),
+		})))
 
 		watchedRoutes := &corev1.ConfigMap{}
 		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: DefaultWatchNamespace, Name: WatchedRoutesConfigMapName}, watchedRoutes)).Should(Succeed())
@@ -221,4 +251,1530 @@ var _ = Describe("RouteAllowlist Controller", Ordered, func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(ok).To(BeFalse())
 	})
+
+	It("will test that ranges removed from the spec are retracted from the route", func() {
+		By("Reconciling the created resource")
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+
+		osRoute.Annotations[AllowlistAnnotation] = "10.33.52.5"
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		request := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: allowlist.Namespace,
+				Name:      allowlist.Name,
+			},
+		}
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: allowlist.Namespace, Name: allowlist.Name}, allowlist)).Should(Succeed())
+
+		By("Replacing the range in the spec")
+
+		allowlist.Spec.IPRanges = []string{"10.100.123.25"}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(strings.Split(osRoute.Annotations[AllowlistAnnotation], " ")).Should(ConsistOf([]string{"10.100.123.25", "10.33.52.5"}))
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: allowlist.Namespace, Name: allowlist.Name}, allowlist)).Should(Succeed())
+
+		By("Removing every range from the spec")
+
+		allowlist.Spec.IPRanges = []string{}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.33.52.5"))
+	})
+
+	It("will test that ranges shared by multiple allowlists are kept until the last one is deleted", func() {
+		By("Reconciling two allowlists sharing a range")
+
+		other := utils.GetRouteAllowlistSpec("other-route", DefaultWatchNamespace, []string{"10.100.123.24", "10.100.123.30"})
+		Expect(fakeClient.Create(ctx, other)).Should(Succeed())
+
+		for _, cr := range []*networkingv1alpha1.RouteAllowlist{allowlist, other} {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(strings.Split(osRoute.Annotations[AllowlistAnnotation], " ")).Should(ConsistOf("10.100.123.24", "10.100.123.30"))
+
+		contributions, err := getContributions(osRoute.Annotations)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(contributions).To(HaveLen(2))
+
+		By("Deleting the allowlist with the fewest ranges")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(fakeClient.Delete(ctx, allowlist)).Should(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(strings.Split(osRoute.Annotations[AllowlistAnnotation], " ")).Should(ConsistOf("10.100.123.24", "10.100.123.30"))
+
+		By("Deleting the remaining allowlist")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(other), other)).Should(Succeed())
+		Expect(fakeClient.Delete(ctx, other)).Should(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(other)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).NotTo(HaveKey(AllowlistAnnotation))
+		Expect(osRoute.Annotations).NotTo(HaveKey(ContributionsAnnotation))
+
+		watchedRoutes := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: DefaultWatchNamespace, Name: WatchedRoutesConfigMapName}, watchedRoutes)).Should(Succeed())
+		Expect(watchedRoutes.Data).ShouldNot(HaveKey(fmt.Sprintf("%s__%s", osRoute.Namespace, osRoute.Name)))
+	})
+
+	It("will test that ranges added by hand are kept when an allowlist leaves a route of allowlists ignoring drift", func() {
+		By("Reconciling an allowlist ignoring drift and a second allowlist")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.DriftPolicy = networkingv1alpha1.DriftPolicyIgnore
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		other := utils.GetRouteAllowlistSpec("other-route", DefaultWatchNamespace, []string{"10.200.0.0/16"})
+		Expect(fakeClient.Create(ctx, other)).Should(Succeed())
+
+		for _, cr := range []*networkingv1alpha1.RouteAllowlist{allowlist, other} {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		By("Adding a range to the route by hand")
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(r), osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24 10.200.0.0/16"))
+		osRoute.Annotations[AllowlistAnnotation] = "10.13.42.54 10.100.123.24 10.200.0.0/16"
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		By("Deleting the second allowlist")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(other), other)).Should(Succeed())
+		Expect(fakeClient.Delete(ctx, other)).Should(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(other)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(r), osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.13.42.54 10.100.123.24"))
+	})
+
+	It("will test that routes falling out of the label selector are restored", func() {
+		By("Reconciling the created resource")
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+
+		osRoute.Annotations[AllowlistAnnotation] = "10.33.52.5"
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.Routes).Should(ConsistOf(HaveField("Name", "test-route")))
+
+		By("Removing the selected label from the route")
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		delete(osRoute.Labels, "ipshield")
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.33.52.5"))
+		Expect(osRoute.Annotations).NotTo(HaveKey(ContributionsAnnotation))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.Routes).Should(BeEmpty())
+
+		watchedRoutes := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: DefaultWatchNamespace, Name: WatchedRoutesConfigMapName}, watchedRoutes)).Should(Succeed())
+		Expect(watchedRoutes.Data).ShouldNot(HaveKey(fmt.Sprintf("%s__%s", osRoute.Namespace, osRoute.Name)))
+	})
+
+	It("will test that ranges are normalized and aggregated on the route", func() {
+		By("Reconciling an allowlist with textual variants and dual stack ranges")
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+
+		osRoute.Annotations[AllowlistAnnotation] = "10.100.123.24/32 10.100.122.0/24"
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		allowlist.Spec.IPRanges = []string{"10.100.123.24", "10.100.123.0/24", "2001:db8::1/128"}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.122.0/23 2001:db8::1"))
+
+		By("Deleting the allowlist")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(fakeClient.Delete(ctx, allowlist)).Should(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.122.0/24 10.100.123.24"))
+	})
+
+	It("will test that unchanged routes and config map are not patched again", func() {
+		By("Reconciling two allowlists")
+
+		other := utils.GetRouteAllowlistSpec("other-route", DefaultWatchNamespace, []string{"10.100.123.30", "10.100.123.26"})
+		Expect(fakeClient.Create(ctx, other)).Should(Succeed())
+
+		for _, cr := range []*networkingv1alpha1.RouteAllowlist{allowlist, other} {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24 10.100.123.26 10.100.123.30"))
+		annotations := osRoute.Annotations
+
+		By("Reconciling both allowlists again in reverse order")
+
+		patched := map[string]int{}
+		reconciler.Client = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				patched[fmt.Sprintf("%T", obj)]++
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		})
+
+		for _, cr := range []*networkingv1alpha1.RouteAllowlist{other, allowlist} {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(cr)})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		Expect(patched).NotTo(HaveKey("*v1.Route"))
+		Expect(patched).NotTo(HaveKey("*v1.ConfigMap"))
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(Equal(annotations))
+	})
+
+	It("will test that the reconciliation is requeued when a route is modified concurrently", func() {
+		By("Updating the route between the read and the patch of the reconciler")
+
+		conflicted := false
+		reconciler.Client = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if concurrent, ok := obj.(*v1.Route); ok && !conflicted {
+					conflicted = true
+					other := &v1.Route{}
+					Expect(c.Get(ctx, client.ObjectKeyFromObject(concurrent), other)).To(Succeed())
+					other.Labels["team"] = "payments"
+					Expect(c.Update(ctx, other)).To(Succeed())
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		})
+
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		result, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(r), osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).NotTo(HaveKey(AllowlistAnnotation))
+
+		By("Reconciling again")
+
+		result, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeFalse())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(r), osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24"))
+		Expect(osRoute.Labels).To(HaveKeyWithValue("team", "payments"))
+	})
+
+	It("will test that routes are migrated to the configured allowlist annotation", func() {
+		By("Reconciling a route with the legacy annotation")
+
+		reconciler.RouteAnnotation = IPAllowlistAnnotation
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+
+		osRoute.Annotations[AllowlistAnnotation] = "10.33.52.5"
+		osRoute.Annotations[IPAllowlistAnnotation] = "10.33.52.6"
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).NotTo(HaveKey(AllowlistAnnotation))
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(IPAllowlistAnnotation, "10.33.52.5 10.33.52.6 10.100.123.24"))
+
+		watchedRoutes := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: DefaultWatchNamespace, Name: WatchedRoutesConfigMapName}, watchedRoutes)).Should(Succeed())
+		Expect(watchedRoutes.Data).To(HaveKeyWithValue(fmt.Sprintf("%s__%s", osRoute.Namespace, osRoute.Name), "10.33.52.5 10.33.52.6"))
+
+		By("Deleting the allowlist")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(fakeClient.Delete(ctx, allowlist)).Should(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).NotTo(HaveKey(AllowlistAnnotation))
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(IPAllowlistAnnotation, "10.33.52.5 10.33.52.6"))
+	})
+
+	It("will test that ranges of referenced IPSets are applied and follow IPSet changes", func() {
+		By("Referencing a missing IPSet")
+
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.IPSetRefs = []string{"office"}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).To(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(apimeta.FindStatusCondition(allowlist.Status.Conditions, networkingv1alpha1.ConditionReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionFalse),
+			"Reason": Equal(networkingv1alpha1.ReasonIPSetFetchFailed),
+		})))
+		Expect(apimeta.IsStatusConditionTrue(allowlist.Status.Conditions, networkingv1alpha1.ConditionDegraded)).To(BeTrue())
+
+		By("Creating the IPSet")
+
+		ipSet := &networkingv1alpha1.IPSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "office", Namespace: DefaultWatchNamespace},
+			Spec: networkingv1alpha1.IPSetSpec{
+				Ranges: []networkingv1alpha1.IPSetRange{
+					{Name: "head-office", CIDR: "10.20.0.0/24"},
+					{Name: "vpn", CIDR: "10.30.0.0/24"},
+				},
+			},
+		}
+		Expect(fakeClient.Create(ctx, ipSet)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.20.0.0/24 10.30.0.0/24 10.100.123.24"))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(apimeta.IsStatusConditionTrue(allowlist.Status.Conditions, networkingv1alpha1.ConditionReady)).To(BeTrue())
+		Expect(apimeta.IsStatusConditionFalse(allowlist.Status.Conditions, networkingv1alpha1.ConditionDegraded)).To(BeTrue())
+
+		By("Changing the IPSet")
+
+		ipSet.Spec.Ranges = ipSet.Spec.Ranges[1:]
+		Expect(fakeClient.Update(ctx, ipSet)).Should(Succeed())
+		Expect(reconciler.mapIPSetToRouteAllowlist(ctx, ipSet)).To(ConsistOf(request))
+
+		unrelated := &networkingv1alpha1.IPSet{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: DefaultWatchNamespace}}
+		Expect(reconciler.mapIPSetToRouteAllowlist(ctx, unrelated)).To(BeEmpty())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.30.0.0/24 10.100.123.24"))
+	})
+
+	It("will test that cluster allowlists share routes and backups with namespaced allowlists", func() {
+		By("Reconciling a cluster allowlist and a namespaced allowlist")
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+
+		osRoute.Annotations[AllowlistAnnotation] = "10.33.52.5"
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		clusterAllowlist := &networkingv1alpha1.ClusterRouteAllowlist{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+			Spec:       allowlist.Spec,
+		}
+		clusterAllowlist.Spec.IPRanges = []string{"10.100.123.24", "10.200.0.0/16"}
+		Expect(fakeClient.Create(ctx, clusterAllowlist)).Should(Succeed())
+
+		clusterReconciler := &ClusterRouteAllowlistReconciler{RouteAllowlistReconciler: *reconciler}
+
+		_, err := clusterReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(clusterAllowlist)})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.33.52.5 10.100.123.24 10.200.0.0/16"))
+
+		contributions, err := getContributions(osRoute.Annotations)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(contributions).To(ConsistOf(
+			contribution{Owner: "platform", Ranges: []string{"10.100.123.24", "10.200.0.0/16"}},
+			contribution{Owner: client.ObjectKeyFromObject(allowlist).String(), Ranges: []string{"10.100.123.24"}},
+		))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(clusterAllowlist), clusterAllowlist)).Should(Succeed())
+		Expect(clusterAllowlist.Status.Routes).To(ConsistOf(HaveField("Namespace", "default")))
+		Expect(clusterAllowlist.Finalizers).To(ContainElement(RouteAllowlistFinalizer))
+
+		watchedRoutes := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: DefaultWatchNamespace, Name: WatchedRoutesConfigMapName}, watchedRoutes)).Should(Succeed())
+		Expect(watchedRoutes.Data).To(HaveKeyWithValue(fmt.Sprintf("%s__%s", osRoute.Namespace, osRoute.Name), "10.33.52.5"))
+		ok, err := controllerutil.HasOwnerReference(watchedRoutes.OwnerReferences, clusterAllowlist, scheme2.Scheme)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+
+		By("Deleting the cluster allowlist")
+
+		Expect(fakeClient.Delete(ctx, clusterAllowlist)).Should(Succeed())
+		_, err = clusterReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(clusterAllowlist)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.33.52.5 10.100.123.24"))
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(clusterAllowlist), clusterAllowlist)).ShouldNot(Succeed())
+
+		By("Deleting the namespaced allowlist")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(fakeClient.Delete(ctx, allowlist)).Should(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.33.52.5"))
+		Expect(osRoute.Annotations).NotTo(HaveKey(ContributionsAnnotation))
+	})
+
+	It("will test that routes outside of the selected namespaces are not managed", func() {
+		By("Selecting routes of namespaces labelled env=prod")
+
+		prod := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "prod", Labels: map[string]string{"env": "prod"}}}
+		dev := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"env": "dev"}}}
+		Expect(fakeClient.Create(ctx, prod)).Should(Succeed())
+		Expect(fakeClient.Create(ctx, dev)).Should(Succeed())
+
+		prodRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, prodRoute)).To(Succeed())
+		prodRoute.Namespace = prod.Name
+		prodRoute.ResourceVersion = ""
+		Expect(fakeClient.Create(ctx, prodRoute)).Should(Succeed())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(prodRoute), osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24"))
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).NotTo(HaveKey(AllowlistAnnotation))
+
+		By("Relabelling the namespace of the remaining route")
+
+		Expect(reconciler.mapNamespaceToRouteAllowlist(ctx, dev)).To(ConsistOf(request))
+
+		dev.Labels["env"] = "prod"
+		Expect(fakeClient.Update(ctx, dev)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24"))
+
+		By("Restricting the allowlist to an explicit list of namespaces")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.Namespaces = []string{prod.Name}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).NotTo(HaveKey(AllowlistAnnotation))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.Routes).To(ConsistOf(HaveField("Namespace", "prod")))
+	})
+
+	It("will test that time-limited entries are applied within their window and retracted on expiry", func() {
+		By("Adding an active and a pending entry")
+
+		now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+		fakeClock := clocktesting.NewFakePassiveClock(now)
+		recorder := record.NewFakeRecorder(10)
+		reconciler.Clock = fakeClock
+		reconciler.Recorder = recorder
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.Entries = []networkingv1alpha1.IPRangeEntry{
+			{CIDR: "10.40.0.0/24", ExpiresAt: &metav1.Time{Time: now.Add(48 * time.Hour)}},
+			{CIDR: "10.50.0.0/24", NotBefore: &metav1.Time{Time: now.Add(time.Hour)}, ExpiresAt: &metav1.Time{Time: now.Add(72 * time.Hour)}},
+		}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		result, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(time.Hour))
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.40.0.0/24 10.100.123.24"))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.Entries).To(HaveLen(2))
+		Expect(allowlist.Status.Entries[0].State).To(Equal(networkingv1alpha1.IPRangeEntryActive))
+		Expect(allowlist.Status.Entries[1].State).To(Equal(networkingv1alpha1.IPRangeEntryPending))
+
+		By("Reaching the start of the pending entry")
+
+		fakeClock.SetTime(now.Add(time.Hour))
+		result, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(47 * time.Hour))
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.40.0.0/24 10.50.0.0/24 10.100.123.24"))
+
+		By("Reaching the expiry of the first entry")
+
+		fakeClock.SetTime(now.Add(48 * time.Hour))
+		result, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(24 * time.Hour))
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.50.0.0/24 10.100.123.24"))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.Entries[0].State).To(Equal(networkingv1alpha1.IPRangeEntryExpired))
+		Expect(recorder.Events).To(Receive(ContainSubstring("EntryExpired IP range 10.40.0.0/24 expired")))
+
+		By("Reconciling again after the expiry")
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("will test that entries allowing the same range within different windows expire separately", func() {
+		By("Reconciling without recorder")
+
+		now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+		fakeClock := clocktesting.NewFakePassiveClock(now)
+		reconciler.Clock = fakeClock
+		reconciler.Recorder = nil
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.Entries = []networkingv1alpha1.IPRangeEntry{
+			{CIDR: "10.40.0.0/24", ExpiresAt: &metav1.Time{Time: now.Add(time.Hour)}},
+			{CIDR: "10.40.0.0/24", ExpiresAt: &metav1.Time{Time: now.Add(2 * time.Hour)}},
+		}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Reaching the expiry of the first entry")
+
+		recorder := record.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		fakeClock.SetTime(now.Add(time.Hour))
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring("expired at 2025-03-01T13:00:00Z")))
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.Entries).To(HaveExactElements(
+			HaveField("State", networkingv1alpha1.IPRangeEntryExpired),
+			HaveField("State", networkingv1alpha1.IPRangeEntryActive),
+		))
+
+		By("Reaching the expiry of the second entry")
+
+		fakeClock.SetTime(now.Add(2 * time.Hour))
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(ContainSubstring("expired at 2025-03-01T14:00:00Z")))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("will test that entry metadata is surfaced in the status and the config map", func() {
+		By("Adding a documented entry")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.Entries = []networkingv1alpha1.IPRangeEntry{{
+			CIDR:        "203.0.113.17",
+			Description: "Payment provider callbacks",
+			Owner:       "payments-team",
+			Reference:   "PAY-1234",
+		}}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24 203.0.113.17"))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.Entries).To(ConsistOf(networkingv1alpha1.IPRangeEntryStatus{
+			IPRangeEntry: allowlist.Spec.Entries[0],
+			State:        networkingv1alpha1.IPRangeEntryActive,
+		}))
+
+		watchedRoutes := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: DefaultWatchNamespace, Name: WatchedRoutesConfigMapName}, watchedRoutes)).Should(Succeed())
+		Expect(watchedRoutes.Data).To(HaveKeyWithValue("default__test-route__entries",
+			`[{"allowlist":"ipshield-cr/test-route","cidr":"203.0.113.17","description":"Payment provider callbacks",`+
+				`"owner":"payments-team","reference":"PAY-1234"}]`))
+
+		By("Deleting the allowlist")
+
+		Expect(fakeClient.Delete(ctx, allowlist)).Should(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: DefaultWatchNamespace, Name: WatchedRoutesConfigMapName}, watchedRoutes)).Should(Succeed())
+		Expect(watchedRoutes.Data).NotTo(HaveKey("default__test-route__entries"))
+	})
+
+	It("will test that the status reports the allowlist applied to every route", func() {
+		By("Reconciling two routes, one of which can't be updated")
+
+		failingRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, failingRoute)).To(Succeed())
+		failingRoute.Name = "failing-route"
+		failingRoute.ResourceVersion = ""
+		failingRoute.Spec.Host = "failing.example.com"
+		Expect(fakeClient.Create(ctx, failingRoute)).Should(Succeed())
+
+		reconciler.Client = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				if obj.GetName() == failingRoute.Name {
+					return fmt.Errorf("route is read-only")
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		})
+
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).To(MatchError("failed to apply the allowlist to 1 route(s)"))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.ObservedGeneration).To(Equal(allowlist.Generation))
+		Expect(allowlist.Status.MatchedRoutes).To(BeEquivalentTo(2))
+		Expect(allowlist.Status.AppliedRoutes).To(BeEquivalentTo(1))
+		Expect(allowlist.Status.FailedRoutes).To(BeEquivalentTo(1))
+		Expect(allowlist.Finalizers).To(ContainElement(RouteAllowlistFinalizer))
+		Expect(apimeta.FindStatusCondition(allowlist.Status.Conditions, networkingv1alpha1.ConditionDegraded)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionTrue),
+			"Reason": Equal(networkingv1alpha1.ReasonRouteUpdateFailed),
+		})))
+		Expect(apimeta.IsStatusConditionTrue(allowlist.Status.Conditions, networkingv1alpha1.ConditionProgressing)).To(BeTrue())
+
+		Expect(allowlist.Status.Routes).To(HaveLen(2))
+		Expect(allowlist.Status.Routes[0]).To(MatchFields(IgnoreExtras, Fields{
+			"Namespace":       Equal("default"),
+			"Name":            Equal("failing-route"),
+			"Host":            Equal("failing.example.com"),
+			"LastAppliedTime": BeNil(),
+			"Error":           Equal("route is read-only"),
+		}))
+		Expect(allowlist.Status.Routes[1]).To(MatchFields(IgnoreExtras, Fields{
+			"Namespace":       Equal("default"),
+			"Name":            Equal("test-route"),
+			"Host":            Equal("test.example.com"),
+			"Allowlist":       Equal("10.100.123.24"),
+			"LastAppliedTime": Not(BeNil()),
+			"Error":           BeEmpty(),
+		}))
+		lastAppliedTime := allowlist.Status.Routes[1].LastAppliedTime
+
+		By("Reconciling again once the route can be updated")
+
+		reconciler.Client = fakeClient
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.AppliedRoutes).To(BeEquivalentTo(2))
+		Expect(allowlist.Status.FailedRoutes).To(BeZero())
+		Expect(allowlist.Status.Routes[0].Error).To(BeEmpty())
+		Expect(allowlist.Status.Routes[0].Allowlist).To(Equal("10.100.123.24"))
+		Expect(allowlist.Status.Routes[1].LastAppliedTime).To(Equal(lastAppliedTime))
+	})
+
+	It("will test that legacy conditions are replaced by the condition model", func() {
+		By("Reconciling an allowlist matching no route with a stale condition")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Status.Conditions = []metav1.Condition{{
+			Type: "Admitted", Status: metav1.ConditionTrue, Reason: "ReconcileSuccessful", LastTransitionTime: metav1.Now(),
+		}}
+		Expect(fakeClient.Status().Update(ctx, allowlist)).Should(Succeed())
+
+		allowlist.Spec.LabelSelector.MatchLabels = map[string]string{"app": "missing"}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.Conditions).To(ConsistOf(
+			MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(networkingv1alpha1.ConditionReady),
+				"Status": Equal(metav1.ConditionTrue),
+				"Reason": Equal(networkingv1alpha1.ReasonNoRoutesMatched),
+			}),
+			MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(networkingv1alpha1.ConditionProgressing),
+				"Status": Equal(metav1.ConditionFalse),
+			}),
+			MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(networkingv1alpha1.ConditionDegraded),
+				"Status": Equal(metav1.ConditionFalse),
+			}),
+		))
+	})
+
+	It("will test that dry-run reports the changes without applying them and paused leaves routes untouched", func() {
+		By("Reconciling the allowlist in DryRun mode")
+
+		recorder := record.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.Mode = networkingv1alpha1.ModeDryRun
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).Should(Succeed())
+		Expect(osRoute.Annotations).NotTo(HaveKey(AllowlistAnnotation))
+		Expect(osRoute.Annotations).NotTo(HaveKey(ContributionsAnnotation))
+
+		configMap := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: DefaultWatchNamespace, Name: WatchedRoutesConfigMapName}, configMap)).Should(Succeed())
+		Expect(configMap.Data).To(BeEmpty())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(apimeta.FindStatusCondition(allowlist.Status.Conditions, networkingv1alpha1.ConditionReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status":  Equal(metav1.ConditionTrue),
+			"Reason":  Equal(networkingv1alpha1.ReasonDryRun),
+			"Message": ContainSubstring("1 route(s) would be updated"),
+		})))
+		Expect(allowlist.Status.Routes).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Name":            Equal("test-route"),
+			"Allowlist":       BeEmpty(),
+			"LastAppliedTime": BeNil(),
+			"PendingChanges": PointTo(MatchAllFields(Fields{
+				"Added":   ConsistOf("10.100.123.24"),
+				"Removed": BeEmpty(),
+			})),
+		})))
+		Expect(allowlist.Status.MatchedRoutes).To(BeEquivalentTo(1))
+		Expect(allowlist.Status.AppliedRoutes).To(BeZero())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Route default/test-route would be updated, added: [10.100.123.24]")))
+
+		By("Reconciling again without any change")
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+
+		By("Enforcing the allowlist")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.Mode = networkingv1alpha1.ModeEnforce
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).Should(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24"))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.Routes).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"PendingChanges": BeNil(),
+		})))
+		Expect(allowlist.Status.AppliedRoutes).To(BeEquivalentTo(1))
+
+		By("Pausing the allowlist before changing its ranges")
+
+		allowlist.Spec.Mode = networkingv1alpha1.ModePaused
+		allowlist.Spec.IPRanges = []string{"10.200.0.0/16"}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).Should(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24"))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(apimeta.FindStatusCondition(allowlist.Status.Conditions, networkingv1alpha1.ConditionReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionTrue),
+			"Reason": Equal(networkingv1alpha1.ReasonPaused),
+		})))
+		Expect(allowlist.Status.ObservedGeneration).To(Equal(allowlist.Generation))
+	})
+
+	It("will test that the replace strategy drops ranges not contributed by an allowlist", func() {
+		By("Reconciling an allowlist replacing a hand-written allowlist")
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		osRoute.Annotations[AllowlistAnnotation] = "0.0.0.0/0"
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.Strategy = networkingv1alpha1.StrategyReplace
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24"))
+
+		watchedRoutes := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: DefaultWatchNamespace, Name: WatchedRoutesConfigMapName}, watchedRoutes)).Should(Succeed())
+		Expect(watchedRoutes.Data).To(HaveKeyWithValue(getRouteFullName(osRoute), "0.0.0.0/0"))
+
+		By("Adding a range to the route by hand")
+
+		osRoute.Annotations[AllowlistAnnotation] = "10.100.123.24 0.0.0.0/0"
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24"))
+
+		By("Adding a merging allowlist selecting the same route")
+
+		other := utils.GetRouteAllowlistSpec("other", DefaultWatchNamespace, []string{"10.200.0.0/16"})
+		Expect(fakeClient.Create(ctx, other)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(other)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24 10.200.0.0/16"))
+
+		By("Deleting the replacing allowlist")
+
+		Expect(fakeClient.Delete(ctx, allowlist)).Should(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "0.0.0.0/0"))
+	})
+
+	It("will test that contributions forged for allowlists not selecting the route are dropped", func() {
+		By("Reconciling an allowlist replacing the allowlist of the route")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.Strategy = networkingv1alpha1.StrategyReplace
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		unrelated := utils.GetRouteAllowlistSpec("unrelated", DefaultWatchNamespace, []string{"10.200.0.0/16"})
+		unrelated.Spec.LabelSelector.MatchLabels = map[string]string{"app": "unrelated"}
+		Expect(fakeClient.Create(ctx, unrelated)).Should(Succeed())
+
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Forging a contribution of the unrelated allowlist opening the route to every client")
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(r), osRoute)).To(Succeed())
+		osRoute.Annotations[AllowlistAnnotation] = "0.0.0.0/0"
+		osRoute.Annotations[ContributionsAnnotation] = `[{"owner":"ipshield-cr/test-route","ranges":["10.100.123.24"],"replace":true},` +
+			`{"owner":"ipshield-cr/unrelated","ranges":["0.0.0.0/0"]}]`
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(unrelated)})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(r), osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24"))
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(ContributionsAnnotation,
+			`[{"owner":"ipshield-cr/test-route","ranges":["10.100.123.24"],"replace":true}]`))
+	})
+
+	It("will test that ranges added by hand to a managed route are reverted with the Merge strategy", func() {
+		By("Reconciling the allowlist")
+
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Opening the route to every client by hand")
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(r), osRoute)).To(Succeed())
+		osRoute.Annotations[AllowlistAnnotation] = "0.0.0.0/0 10.100.123.24"
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(r), osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24"))
+	})
+
+	It("will test that drift is reported, ignored or enforced following the drift policy", func() {
+		By("Reconciling an allowlist reporting drift")
+
+		recorder := record.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		reconciler.ResyncPeriod = time.Minute
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.DriftPolicy = networkingv1alpha1.DriftPolicyReport
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		result, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(time.Minute))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(apimeta.FindStatusCondition(allowlist.Status.Conditions, networkingv1alpha1.ConditionDriftDetected)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionFalse),
+			"Reason": Equal(networkingv1alpha1.ReasonNoDrift),
+		})))
+
+		By("Replacing the allowlist of the route by hand")
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		osRoute.Annotations[AllowlistAnnotation] = "10.13.42.54"
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.13.42.54"))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(apimeta.FindStatusCondition(allowlist.Status.Conditions, networkingv1alpha1.ConditionDriftDetected)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status":  Equal(metav1.ConditionTrue),
+			"Reason":  Equal(networkingv1alpha1.ReasonAllowlistModified),
+			"Message": ContainSubstring("default/test-route"),
+		})))
+		Expect(allowlist.Status.Routes).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Drift": PointTo(MatchAllFields(Fields{
+				"Added":   ConsistOf("10.13.42.54"),
+				"Removed": ConsistOf("10.100.123.24"),
+			})),
+		})))
+		Expect(recorder.Events).To(Receive(And(HavePrefix("Warning DriftDetected"), ContainSubstring("added: [10.13.42.54], removed: [10.100.123.24]"))))
+
+		By("Adding a range to the allowlist while the drift is kept")
+
+		allowlist.Spec.IPRanges = append(allowlist.Spec.IPRanges, "10.200.0.0/16")
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.13.42.54 10.200.0.0/16"))
+		Expect(recorder.Events).NotTo(Receive())
+
+		By("Removing the drift condition in the modes not looking for drift")
+
+		for _, mode := range []networkingv1alpha1.Mode{networkingv1alpha1.ModePaused, networkingv1alpha1.ModeDryRun} {
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+			allowlist.Spec.Mode = mode
+			Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+			Expect(apimeta.FindStatusCondition(allowlist.Status.Conditions, networkingv1alpha1.ConditionDriftDetected)).To(BeNil())
+		}
+		allowlist.Spec.Mode = networkingv1alpha1.ModeEnforce
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("Ignoring the drift")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.DriftPolicy = networkingv1alpha1.DriftPolicyIgnore
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.13.42.54 10.200.0.0/16"))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(apimeta.FindStatusCondition(allowlist.Status.Conditions, networkingv1alpha1.ConditionDriftDetected)).To(BeNil())
+		Expect(allowlist.Status.Routes[0].Drift).To(BeNil())
+
+		By("Enforcing the allowlist")
+
+		allowlist.Spec.DriftPolicy = networkingv1alpha1.DriftPolicyEnforce
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24 10.200.0.0/16"))
+	})
+
+	It("will test that the original allowlist recorded at admission is backed up", func() {
+		By("Applying the allowlists to the route as the admission webhook does")
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		osRoute.Annotations[AllowlistAnnotation] = "0.0.0.0/0"
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.Strategy = networkingv1alpha1.StrategyReplace
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		Expect(reconciler.ApplyAllowlists(ctx, osRoute)).To(BeTrue())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24"))
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(OriginalAllowlistAnnotation, "0.0.0.0/0"))
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		By("Reconciling the allowlist")
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24"))
+		Expect(osRoute.Annotations).NotTo(HaveKey(OriginalAllowlistAnnotation))
+
+		watchedRoutes := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: DefaultWatchNamespace, Name: WatchedRoutesConfigMapName}, watchedRoutes)).Should(Succeed())
+		Expect(watchedRoutes.Data).To(HaveKeyWithValue(getRouteFullName(osRoute), "0.0.0.0/0"))
+	})
+
+	It("will test that ingresses are managed alongside routes and restored once no longer targeted", func() {
+		By("Reconciling an allowlist targeting routes and ingresses")
+
+		ingress := &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-ingress",
+				Namespace: "default",
+				Labels: map[string]string{
+					"ipshield":                   "true",
+					IPShieldWatchedResourceLabel: "true",
+				},
+				Annotations: map[string]string{
+					backend.IngressAllowlistAnnotation: "192.168.0.0/24, 172.16.0.0/16",
+				},
+			},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{{Host: "ingress.example.com"}},
+			},
+		}
+		Expect(fakeClient.Create(ctx, ingress)).Should(Succeed())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.Targets = []networkingv1alpha1.TargetKind{networkingv1alpha1.TargetRoute, networkingv1alpha1.TargetIngress}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(ingress), ingress)).To(Succeed())
+		Expect(ingress.Annotations).To(HaveKeyWithValue(backend.IngressAllowlistAnnotation, "10.100.123.24,172.16.0.0/16,192.168.0.0/24"))
+		Expect(ingress.Annotations).To(HaveKey(ContributionsAnnotation))
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: r.GetNamespace(), Name: r.GetName()}, osRoute)).To(Succeed())
+		Expect(osRoute.Annotations).To(HaveKeyWithValue(AllowlistAnnotation, "10.100.123.24"))
+
+		watchedIngresses := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: DefaultWatchNamespace, Name: backend.WatchedIngressesConfigMapName}, watchedIngresses)).Should(Succeed())
+		Expect(watchedIngresses.Data).To(HaveKeyWithValue(getRouteFullName(ingress), "172.16.0.0/16 192.168.0.0/24"))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.MatchedRoutes).To(Equal(int32(2)))
+		Expect(allowlist.Status.Routes).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Kind":      Equal(networkingv1alpha1.TargetIngress),
+			"Name":      Equal("test-ingress"),
+			"Host":      Equal("ingress.example.com"),
+			"Allowlist": Equal("10.100.123.24 172.16.0.0/16 192.168.0.0/24"),
+		})))
+
+		By("Removing ingresses from the targets")
+
+		allowlist.Spec.Targets = []networkingv1alpha1.TargetKind{networkingv1alpha1.TargetRoute}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(ingress), ingress)).To(Succeed())
+		Expect(ingress.Annotations).To(HaveKeyWithValue(backend.IngressAllowlistAnnotation, "172.16.0.0/16,192.168.0.0/24"))
+		Expect(ingress.Annotations).NotTo(HaveKey(ContributionsAnnotation))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.Routes).To(ConsistOf(HaveField("Kind", networkingv1alpha1.TargetRoute)))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(watchedIngresses), watchedIngresses)).Should(Succeed())
+		Expect(watchedIngresses.Data).NotTo(HaveKey(getRouteFullName(ingress)))
+
+		By("Targeting ingresses again and deleting the allowlist")
+
+		allowlist.Spec.Targets = []networkingv1alpha1.TargetKind{networkingv1alpha1.TargetIngress}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(ingress), ingress)).To(Succeed())
+		Expect(ingress.Annotations).To(HaveKeyWithValue(backend.IngressAllowlistAnnotation, "10.100.123.24,172.16.0.0/16,192.168.0.0/24"))
+
+		Expect(fakeClient.Delete(ctx, allowlist)).Should(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(ingress), ingress)).To(Succeed())
+		Expect(ingress.Annotations).To(HaveKeyWithValue(backend.IngressAllowlistAnnotation, "172.16.0.0/16,192.168.0.0/24"))
+		Expect(ingress.Annotations).NotTo(HaveKey(ContributionsAnnotation))
+	})
+
+	It("will test that backends added to the operator enforce the allowlists on their targets", func() {
+		By("Reconciling an allowlist targeting a kind without backend")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.Targets = []networkingv1alpha1.TargetKind{"Service"}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).To(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(apimeta.FindStatusCondition(allowlist.Status.Conditions, networkingv1alpha1.ConditionReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionFalse),
+			"Reason": Equal(networkingv1alpha1.ReasonInvalidSpec),
+		})))
+
+		By("Adding a backend for the kind")
+
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-service",
+				Namespace: "default",
+				Labels: map[string]string{
+					"ipshield":                   "true",
+					IPShieldWatchedResourceLabel: "true",
+				},
+				Annotations: map[string]string{
+					serviceAllowlistAnnotation: "192.168.0.0/24",
+				},
+			},
+		}
+		Expect(fakeClient.Create(ctx, service)).Should(Succeed())
+
+		reconciler.Backends = append(backend.Defaults(""), &serviceBackend{})
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(service), service)).To(Succeed())
+		Expect(service.Annotations).To(HaveKeyWithValue(serviceAllowlistAnnotation, "10.100.123.24 192.168.0.0/24"))
+
+		watchedServices := &corev1.ConfigMap{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: DefaultWatchNamespace, Name: "watched-services"}, watchedServices)).Should(Succeed())
+		Expect(watchedServices.Data).To(HaveKeyWithValue(getRouteFullName(service), "192.168.0.0/24"))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.Routes).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Kind": Equal(networkingv1alpha1.TargetKind("Service")),
+			"Name": Equal("test-service"),
+		})))
+
+		By("Deleting the allowlist")
+
+		Expect(fakeClient.Delete(ctx, allowlist)).Should(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(service), service)).To(Succeed())
+		Expect(service.Annotations).To(HaveKeyWithValue(serviceAllowlistAnnotation, "192.168.0.0/24"))
+		Expect(service.Annotations).NotTo(HaveKey(ContributionsAnnotation))
+	})
+
+	It("will test that HTTPRoutes are protected by a generated SecurityPolicy deleted with the allowlist", func() {
+		By("Reconciling an allowlist targeting HTTPRoutes")
+
+		for _, gvk := range []schema.GroupVersionKind{backend.HTTPRouteGVK, backend.SecurityPolicyGVK} {
+			scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+			scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+		}
+
+		httpRoute := &unstructured.Unstructured{}
+		httpRoute.SetUnstructuredContent(map[string]interface{}{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "HTTPRoute",
+			"metadata": map[string]interface{}{
+				"name":      "test-httproute",
+				"namespace": "default",
+				"uid":       "d5c2e2a6-7b2b-4a4c-9f38-2f0b1d0c8e11",
+				"labels": map[string]interface{}{
+					"ipshield":                   "true",
+					IPShieldWatchedResourceLabel: "true",
+				},
+			},
+			"spec": map[string]interface{}{
+				"hostnames": []interface{}{"gateway.example.com"},
+			},
+		})
+		Expect(fakeClient.Create(ctx, httpRoute)).Should(Succeed())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.Targets = []networkingv1alpha1.TargetKind{networkingv1alpha1.TargetHTTPRoute}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		reconciler.Backends = append(backend.Defaults(""), &backend.HTTPRoute{})
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		policy := &unstructured.Unstructured{}
+		policy.SetGroupVersionKind(backend.SecurityPolicyGVK)
+		policyKey := types.NamespacedName{Namespace: "default", Name: backend.SecurityPolicyPrefix + "test-httproute"}
+		Expect(fakeClient.Get(ctx, policyKey, policy)).Should(Succeed())
+		Expect(policy.GetLabels()).To(HaveKeyWithValue(backend.ManagedByLabel, backend.ManagedByValue))
+		Expect(policy.GetOwnerReferences()).To(ConsistOf(HaveField("Name", "test-httproute")))
+		Expect(policy.Object).To(HaveKeyWithValue("spec", MatchAllKeys(Keys{
+			"targetRefs": ConsistOf(MatchAllKeys(Keys{
+				"group": Equal("gateway.networking.k8s.io"),
+				"kind":  Equal("HTTPRoute"),
+				"name":  Equal("test-httproute"),
+			})),
+			"authorization": MatchAllKeys(Keys{
+				"defaultAction": Equal("Deny"),
+				"rules": ConsistOf(MatchAllKeys(Keys{
+					"name":      Equal("ipshield-allowlist"),
+					"action":    Equal("Allow"),
+					"principal": HaveKeyWithValue("clientCIDRs", ConsistOf("10.100.123.24/32")),
+				})),
+			}),
+		})))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.Routes).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Kind":      Equal(networkingv1alpha1.TargetHTTPRoute),
+			"Host":      Equal("gateway.example.com"),
+			"Allowlist": Equal("10.100.123.24"),
+		})))
+
+		By("Reconciling again without changes")
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		resourceVersion := policy.GetResourceVersion()
+		Expect(fakeClient.Get(ctx, policyKey, policy)).Should(Succeed())
+		Expect(policy.GetResourceVersion()).To(Equal(resourceVersion))
+
+		By("Deleting the allowlist")
+
+		Expect(fakeClient.Delete(ctx, allowlist)).Should(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(errors.IsNotFound(fakeClient.Get(ctx, policyKey, policy))).To(BeTrue())
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(httpRoute), httpRoute)).Should(Succeed())
+		Expect(httpRoute.GetAnnotations()).NotTo(HaveKey(ContributionsAnnotation))
+	})
+
+	It("will test that AuthorizationPolicies are generated for the workloads behind the routes and deleted with the allowlist", func() {
+		By("Reconciling an allowlist requesting AuthorizationPolicies")
+
+		scheme.AddKnownTypeWithName(AuthorizationPolicyGVK, &unstructured.Unstructured{})
+		scheme.AddKnownTypeWithName(AuthorizationPolicyGVK.GroupVersion().WithKind(AuthorizationPolicyGVK.Kind+"List"),
+			&unstructured.UnstructuredList{})
+
+		Expect(fakeClient.Create(ctx, &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "test"}},
+		})).Should(Succeed())
+
+		osRoute := &v1.Route{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(r), osRoute)).Should(Succeed())
+		osRoute.Spec.To = v1.RouteTargetReference{Kind: "Service", Name: "test-service"}
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.UID = "0b6f3c1e-5d0a-4f8e-9a51-7c2d9e4b8a30"
+		allowlist.Spec.AuthorizationPolicy = &networkingv1alpha1.AuthorizationPolicySpec{IPSource: networkingv1alpha1.IPSourcePeer}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		By("Reporting the spec as invalid while Istio isn't installed")
+
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).To(HaveOccurred())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(apimeta.FindStatusCondition(allowlist.Status.Conditions, networkingv1alpha1.ConditionDegraded)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionTrue),
+			"Reason": Equal(networkingv1alpha1.ReasonInvalidSpec),
+		})))
+
+		By("Generating the policy once Istio is installed")
+
+		reconciler.AuthorizationPolicies = true
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		policy := &unstructured.Unstructured{}
+		policy.SetGroupVersionKind(AuthorizationPolicyGVK)
+		policyKey := types.NamespacedName{Namespace: "default", Name: getAuthorizationPolicyName(allowlist, osRoute)}
+		Expect(fakeClient.Get(ctx, policyKey, policy)).Should(Succeed())
+		Expect(policy.GetLabels()).To(HaveKeyWithValue(AuthorizationPolicyOwnerLabel, string(allowlist.UID)))
+		Expect(policy.Object).To(HaveKeyWithValue("spec", MatchAllKeys(Keys{
+			"selector": HaveKeyWithValue("matchLabels", HaveKeyWithValue("app", "test")),
+			"action":   Equal("ALLOW"),
+			"rules": ConsistOf(HaveKeyWithValue("from", ConsistOf(
+				HaveKeyWithValue("source", HaveKeyWithValue("ipBlocks", ConsistOf("10.100.123.24"))),
+			))),
+		})))
+		Expect(policyKey.Name).To(HavePrefix("ipshield-routeallowlist-"))
+		Expect(getAuthorizationPolicyName(&networkingv1alpha1.ClusterRouteAllowlist{
+			ObjectMeta: metav1.ObjectMeta{Name: allowlist.Name},
+		}, osRoute)).To(HavePrefix("ipshield-clusterrouteallowlist-"))
+
+		By("Deleting the policy once the enabled label is removed from the route")
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(osRoute), osRoute)).Should(Succeed())
+		delete(osRoute.Labels, IPShieldWatchedResourceLabel)
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(errors.IsNotFound(fakeClient.Get(ctx, policyKey, policy))).To(BeTrue())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(osRoute), osRoute)).Should(Succeed())
+		osRoute.Labels[IPShieldWatchedResourceLabel] = "true"
+		Expect(fakeClient.Update(ctx, osRoute)).Should(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeClient.Get(ctx, policyKey, policy)).Should(Succeed())
+
+		By("Deleting the allowlist")
+
+		Expect(fakeClient.Delete(ctx, allowlist)).Should(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(errors.IsNotFound(fakeClient.Get(ctx, policyKey, policy))).To(BeTrue())
+	})
+
+	It("will test that IngressRoutes get a generated Middleware attached and detached with the allowlist", func() {
+		By("Reconciling an allowlist targeting IngressRoutes")
+
+		for _, gvk := range []schema.GroupVersionKind{backend.IngressRouteGVK, backend.MiddlewareGVK} {
+			scheme.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+			scheme.AddKnownTypeWithName(gvk.GroupVersion().WithKind(gvk.Kind+"List"), &unstructured.UnstructuredList{})
+		}
+
+		ingressRoute := &unstructured.Unstructured{}
+		ingressRoute.SetUnstructuredContent(map[string]interface{}{
+			"apiVersion": "traefik.io/v1alpha1",
+			"kind":       "IngressRoute",
+			"metadata": map[string]interface{}{
+				"name":      "test-ingressroute",
+				"namespace": "default",
+				"uid":       "8e0c4f7a-2b6d-4c1e-a3f9-5d7b1e2c9a46",
+				"labels": map[string]interface{}{
+					"ipshield":                   "true",
+					IPShieldWatchedResourceLabel: "true",
+				},
+			},
+			"spec": map[string]interface{}{
+				"routes": []interface{}{
+					map[string]interface{}{
+						"match":       "Host(`traefik.example.com`)",
+						"kind":        "Rule",
+						"middlewares": []interface{}{map[string]interface{}{"name": "compress"}},
+					},
+					map[string]interface{}{
+						"match": "Host(`traefik.example.com`) && PathPrefix(`/api`)",
+						"kind":  "Rule",
+					},
+				},
+			},
+		})
+		Expect(fakeClient.Create(ctx, ingressRoute)).Should(Succeed())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		allowlist.Spec.Targets = []networkingv1alpha1.TargetKind{networkingv1alpha1.TargetIngressRoute}
+		Expect(fakeClient.Update(ctx, allowlist)).Should(Succeed())
+
+		reconciler.Backends = append(backend.Defaults(""), &backend.Traefik{})
+		request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(allowlist)}
+		_, err := reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		middleware := &unstructured.Unstructured{}
+		middleware.SetGroupVersionKind(backend.MiddlewareGVK)
+		middlewareKey := types.NamespacedName{Namespace: "default", Name: backend.MiddlewarePrefix + "test-ingressroute"}
+		Expect(fakeClient.Get(ctx, middlewareKey, middleware)).Should(Succeed())
+		Expect(middleware.GetLabels()).To(HaveKeyWithValue(backend.ManagedByLabel, backend.ManagedByValue))
+		Expect(middleware.GetOwnerReferences()).To(ConsistOf(HaveField("Name", "test-ingressroute")))
+		sourceRange, _, err := unstructured.NestedStringSlice(middleware.Object, "spec", "ipAllowList", "sourceRange")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sourceRange).To(ConsistOf("10.100.123.24"))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(ingressRoute), ingressRoute)).Should(Succeed())
+		routes, _, err := unstructured.NestedSlice(ingressRoute.Object, "spec", "routes")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(routes).To(ConsistOf(
+			HaveKeyWithValue("middlewares", Equal([]interface{}{
+				map[string]interface{}{"name": middlewareKey.Name},
+				map[string]interface{}{"name": "compress"},
+			})),
+			HaveKeyWithValue("middlewares", Equal([]interface{}{
+				map[string]interface{}{"name": middlewareKey.Name},
+			})),
+		))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(allowlist), allowlist)).Should(Succeed())
+		Expect(allowlist.Status.Routes).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Kind":      Equal(networkingv1alpha1.TargetIngressRoute),
+			"Host":      Equal("Host(`traefik.example.com`)"),
+			"Allowlist": Equal("10.100.123.24"),
+		})))
+
+		By("Deleting the allowlist")
+
+		Expect(fakeClient.Delete(ctx, allowlist)).Should(Succeed())
+		_, err = reconciler.Reconcile(ctx, request)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(errors.IsNotFound(fakeClient.Get(ctx, middlewareKey, middleware))).To(BeTrue())
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(ingressRoute), ingressRoute)).Should(Succeed())
+		routes, _, err = unstructured.NestedSlice(ingressRoute.Object, "spec", "routes")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(routes).To(ConsistOf(
+			HaveKeyWithValue("middlewares", Equal([]interface{}{map[string]interface{}{"name": "compress"}})),
+			Not(HaveKey("middlewares")),
+		))
+	})
 })
+
+const serviceAllowlistAnnotation = "example.com/allowlist"
+
+// serviceBackend is a backend as a team would add to the operator, writing the allowlist to an annotation of services
+type serviceBackend struct{}
+
+func (b *serviceBackend) Kind() networkingv1alpha1.TargetKind {
+	return "Service"
+}
+
+func (b *serviceBackend) ConfigMapName() string {
+	return "watched-services"
+}
+
+func (b *serviceBackend) NewObject() client.Object {
+	return &corev1.Service{}
+}
+
+func (b *serviceBackend) List(ctx context.Context, c client.Client, selector labels.Selector) ([]client.Object, error) {
+	services := &corev1.ServiceList{}
+	if err := c.List(ctx, services, &client.ListOptions{LabelSelector: selector}); err != nil {
+		return nil, err
+	}
+
+	var result []client.Object
+	for i := range services.Items {
+		result = append(result, &services.Items[i])
+	}
+	return result, nil
+}
+
+func (b *serviceBackend) GetAllowlist(_ context.Context, _ client.Client, target client.Object) ([]string, error) {
+	return strings.Fields(target.GetAnnotations()[serviceAllowlistAnnotation]), nil
+}
+
+func (b *serviceBackend) Apply(_ context.Context, _ client.Client, _, target client.Object, allowlist []string) error {
+	target.GetAnnotations()[serviceAllowlistAnnotation] = strings.Join(allowlist, " ")
+	return nil
+}
+
+func (b *serviceBackend) Restore(ctx context.Context, c client.Client, owner, target client.Object, original []string) error {
+	return b.Apply(ctx, c, owner, target, original)
+}
+
+func (b *serviceBackend) GetHost(_ client.Object) string {
+	return ""
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"slices"
+
+	set "github.com/deckarep/golang-set/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+	"github.com/stakater/ipshield-operator/pkg/backend"
+)
+
+// getBackends returns the backends enforcing the allowlists
+func (r *RouteAllowlistReconciler) getBackends() []backend.Backend {
+	if r.Backends == nil {
+		return backend.Defaults(r.getRouteAnnotation())
+	}
+	return r.Backends
+}
+
+// isTargeted returns whether the CR selects the targets of the kind, only routes are selected when no kind is set
+func isTargeted(cr allowlistObject, kind networkingv1alpha1.TargetKind) bool {
+	targets := cr.GetSpec().Targets
+	if len(targets) == 0 {
+		return kind == networkingv1alpha1.TargetRoute
+	}
+	return slices.Contains(targets, kind)
+}
+
+// getUnsupportedTargets returns the kinds listed in the spec of the CR that no backend enforces
+func (r *RouteAllowlistReconciler) getUnsupportedTargets(cr allowlistObject) []networkingv1alpha1.TargetKind {
+	backends := r.getBackends()
+	return slices.DeleteFunc(slices.Clone(cr.GetSpec().Targets), func(kind networkingv1alpha1.TargetKind) bool {
+		return slices.ContainsFunc(backends, func(b backend.Backend) bool { return b.Kind() == kind })
+	})
+}
+
+// selection holds the targets of a backend selected by the CR, and those it managed previously that are no longer
+// selected
+type selection struct {
+	backend    backend.Backend
+	selected   []client.Object
+	unselected []client.Object
+}
+
+// isManaged returns whether the selection holds targets to update or restore
+func (s selection) isManaged() bool {
+	return len(s.selected) > 0 || len(s.unselected) > 0
+}
+
+// countSelected returns the number of targets of every backend selected by the CR
+func countSelected(selections []selection) int {
+	count := 0
+	for _, s := range selections {
+		count += len(s.selected)
+	}
+	return count
+}
+
+// filterByNamespace drops the targets outside of the selected namespaces
+func filterByNamespace(objects []client.Object, namespaces set.Set[string]) []client.Object {
+	if namespaces == nil {
+		return objects
+	}
+
+	return slices.DeleteFunc(objects, func(item client.Object) bool {
+		return !namespaces.Contains(item.GetNamespace())
+	})
+}
+
+func getAnnotations(obj client.Object) map[string]string {
+	if obj.GetAnnotations() == nil {
+		obj.SetAnnotations(make(map[string]string))
+	}
+	return obj.GetAnnotations()
+}
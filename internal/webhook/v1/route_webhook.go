@@ -0,0 +1,200 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	route "github.com/openshift/api/route/v1"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/stakater/ipshield-operator/internal/controller"
+)
+
+var routelog = logf.Log.WithName("route-resource")
+
+// SetupRouteWebhookWithManager registers the webhooks for Route in the manager
+func SetupRouteWebhookWithManager(mgr ctrl.Manager, defaulter *RouteCustomDefaulter, validator *RouteCustomValidator) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&route.Route{}).
+		WithDefaulter(defaulter).
+		WithValidator(validator).
+		Complete()
+}
+
+// Routes are still admitted when the operator is unavailable, the reconcilers protect them once it's back
+//+kubebuilder:webhook:path=/mutate-route-openshift-io-v1-route,mutating=true,failurePolicy=ignore,sideEffects=None,groups=route.openshift.io,resources=routes,verbs=create;update,versions=v1,name=mroute.networking.stakater.com,admissionReviewVersions=v1
+
+// RouteCustomDefaulter injects the allowlist of the matching RouteAllowlists and ClusterRouteAllowlists into
+// routes labelled with ipshield.stakater.cloud/enabled=true, so they are never persisted unprotected.
+type RouteCustomDefaulter struct {
+	// Reconciler computes the allowlists, so they match the allowlists it applies
+	Reconciler *controller.RouteAllowlistReconciler
+}
+
+var _ webhook.CustomDefaulter = &RouteCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type Route.
+func (d *RouteCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	watchedRoute, ok := obj.(*route.Route)
+	if !ok {
+		return fmt.Errorf("expected a Route object but got %T", obj)
+	}
+
+	// Routes are created without contributions, those set by the creator would prevent the allowlists from being
+	// injected
+	if req, err := admission.RequestFromContext(ctx); err == nil && req.Operation == admissionv1.Create {
+		delete(watchedRoute.Annotations, controller.ContributionsAnnotation)
+		delete(watchedRoute.Annotations, controller.OriginalAllowlistAnnotation)
+	}
+
+	updated, err := d.Reconciler.ApplyAllowlists(ctx, watchedRoute)
+	if err != nil {
+		// The route is admitted unchanged, the reconcilers apply the allowlist once the error is resolved
+		routelog.Error(err, "Failed to apply allowlist upon admission", "namespace", watchedRoute.GetNamespace(),
+			"name", watchedRoute.GetName())
+		return nil
+	}
+	if updated {
+		routelog.Info("Allowlist applied upon admission", "namespace", watchedRoute.GetNamespace(), "name", watchedRoute.GetName())
+	}
+	return nil
+}
+
+// Routes are only validated while the operator is available, so they can still be updated when it isn't
+//+kubebuilder:webhook:path=/validate-route-openshift-io-v1-route,mutating=false,failurePolicy=ignore,sideEffects=None,groups=route.openshift.io,resources=routes,verbs=create;update,versions=v1,name=vroute.networking.stakater.com,admissionReviewVersions=v1
+
+// RouteCustomValidator denies routes violating an IPShieldPolicy and, when enabled, routes whose contributions
+// weren't set by IPShield or updates removing the enabled label of a route protected by IPShield or ranges contributed
+// to it by an allowlist.
+type RouteCustomValidator struct {
+	// Enabled turns on the protection against tampering
+	Enabled bool
+	// BypassUsers and BypassGroups are neither restricted by the protection nor by the policies, the service
+	// account of the operator is one of them. Only the BypassUsers may change the contributions
+	BypassUsers  []string
+	BypassGroups []string
+	// Policies evaluates the IPShieldPolicies, they aren't enforced if nil
+	Policies *controller.IPShieldPolicyReconciler
+	// Allowlists tells the contributions written upon admission from forged ones, contributions can only be set
+	// by the BypassUsers if nil
+	Allowlists *controller.RouteAllowlistReconciler
+}
+
+var _ webhook.CustomValidator = &RouteCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Route.
+func (v *RouteCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	newRoute, ok := obj.(*route.Route)
+	if !ok {
+		return nil, fmt.Errorf("expected a Route object but got %T", obj)
+	}
+
+	return nil, v.validate(ctx, nil, newRoute)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Route.
+func (v *RouteCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldRoute, ok := oldObj.(*route.Route)
+	if !ok {
+		return nil, fmt.Errorf("expected a Route object for the oldObj but got %T", oldObj)
+	}
+	newRoute, ok := newObj.(*route.Route)
+	if !ok {
+		return nil, fmt.Errorf("expected a Route object for the newObj but got %T", newObj)
+	}
+
+	return nil, v.validate(ctx, oldRoute, newRoute)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Route.
+func (v *RouteCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *RouteCustomValidator) validate(ctx context.Context, oldRoute, newRoute *route.Route) error {
+	req, reqErr := admission.RequestFromContext(ctx)
+
+	// The contributions are maintained by the operator, the bypass groups may not set them either
+	if v.Enabled && (reqErr != nil || !slices.Contains(v.BypassUsers, req.UserInfo.Username)) {
+		if err := v.validateContributions(ctx, oldRoute, newRoute); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if v.Enabled && oldRoute != nil {
+		err = controller.ValidateRouteUpdate(oldRoute, newRoute)
+	}
+	if err == nil && v.Policies != nil {
+		err = v.Policies.ValidateRoute(ctx, newRoute)
+		var violation *controller.PolicyViolationError
+		if err != nil && !errors.As(err, &violation) {
+			routelog.Error(err, "Failed to evaluate IPShieldPolicies", "namespace", newRoute.GetNamespace(),
+				"name", newRoute.GetName())
+			return apierrors.NewInternalError(err)
+		}
+	}
+	if err == nil {
+		return nil
+	}
+
+	if reqErr == nil && v.isBypassed(req.UserInfo) {
+		routelog.Info("Protection bypassed", "namespace", newRoute.GetNamespace(), "name", newRoute.GetName(),
+			"user", req.UserInfo.Username, "reason", err.Error())
+		return nil
+	}
+
+	return apierrors.NewForbidden(route.Resource("routes"), newRoute.GetName(), err)
+}
+
+func (v *RouteCustomValidator) validateContributions(ctx context.Context, oldRoute, newRoute *route.Route) error {
+	// Without the allowlists, contributions written upon admission can't be told from forged ones
+	var previous string
+	if oldRoute != nil {
+		previous = oldRoute.Annotations[controller.ContributionsAnnotation]
+	}
+	valid := previous == newRoute.Annotations[controller.ContributionsAnnotation]
+
+	if v.Allowlists != nil {
+		var err error
+		valid, err = v.Allowlists.ValidateContributions(ctx, oldRoute, newRoute)
+		if err != nil {
+			routelog.Error(err, "Failed to validate contributions", "namespace", newRoute.GetNamespace(), "name", newRoute.GetName())
+			return apierrors.NewInternalError(err)
+		}
+	}
+	if valid {
+		return nil
+	}
+	return apierrors.NewForbidden(route.Resource("routes"), newRoute.GetName(),
+		fmt.Errorf("annotation %s can only be set by IPShield", controller.ContributionsAnnotation))
+}
+
+func (v *RouteCustomValidator) isBypassed(userInfo authenticationv1.UserInfo) bool {
+	return slices.Contains(v.BypassUsers, userInfo.Username) ||
+		slices.ContainsFunc(userInfo.Groups, func(group string) bool { return slices.Contains(v.BypassGroups, group) })
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	route "github.com/openshift/api/route/v1"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+	"github.com/stakater/ipshield-operator/internal/controller"
+	"github.com/stakater/ipshield-operator/test/utils"
+)
+
+var _ = Describe("Route Webhook", func() {
+	var (
+		ctx          context.Context
+		watchedRoute *route.Route
+		defaulter    RouteCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(route.AddToScheme(scheme)).To(Succeed())
+		Expect(networkingv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		dryRun := utils.GetRouteAllowlistSpec("dry-run", controller.DefaultWatchNamespace, []string{"10.200.0.0/16"})
+		dryRun.Spec.Mode = networkingv1alpha1.ModeDryRun
+
+		fakeClient := fakeclient.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				utils.GetRouteAllowlistSpec("test-route", controller.DefaultWatchNamespace, []string{"10.100.123.24"}),
+				utils.GetRouteAllowlistSpec("other-namespace", "default", []string{"10.210.0.0/16"}),
+				dryRun,
+			).
+			Build()
+
+		watchedRoute = &route.Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-route",
+				Namespace: "default",
+				Labels: map[string]string{
+					"ipshield":                              "true",
+					controller.IPShieldWatchedResourceLabel: "true",
+				},
+				Annotations: map[string]string{controller.AllowlistAnnotation: "10.33.52.5"},
+			},
+		}
+		defaulter = RouteCustomDefaulter{Reconciler: &controller.RouteAllowlistReconciler{
+			Client:         fakeClient,
+			Scheme:         scheme,
+			WatchNamespace: controller.DefaultWatchNamespace,
+		}}
+	})
+
+	It("should inject the allowlist of the enforced allowlists into a labelled route", func() {
+		Expect(defaulter.Default(ctx, watchedRoute)).To(Succeed())
+
+		Expect(watchedRoute.Annotations).To(HaveKeyWithValue(controller.AllowlistAnnotation, "10.33.52.5 10.100.123.24"))
+		Expect(watchedRoute.Annotations).To(HaveKeyWithValue(controller.OriginalAllowlistAnnotation, "10.33.52.5"))
+		Expect(watchedRoute.Annotations).To(HaveKeyWithValue(controller.ContributionsAnnotation,
+			`[{"owner":"ipshield-cr/test-route","ranges":["10.100.123.24"]}]`))
+	})
+
+	It("should leave routes that aren't labelled alone", func() {
+		delete(watchedRoute.Labels, controller.IPShieldWatchedResourceLabel)
+
+		Expect(defaulter.Default(ctx, watchedRoute)).To(Succeed())
+		Expect(watchedRoute.Annotations).To(Equal(map[string]string{controller.AllowlistAnnotation: "10.33.52.5"}))
+	})
+
+	It("should admit routes unchanged when an allowlist can't be evaluated", func() {
+		broken := utils.GetRouteAllowlistSpec("broken", controller.DefaultWatchNamespace, nil)
+		broken.Spec.IPSetRefs = []string{"missing"}
+		Expect(defaulter.Reconciler.Create(ctx, broken)).To(Succeed())
+
+		Expect(defaulter.Default(ctx, watchedRoute)).To(Succeed())
+		Expect(watchedRoute.Annotations).To(Equal(map[string]string{controller.AllowlistAnnotation: "10.33.52.5"}))
+	})
+
+	It("should leave routes already managed by the reconcilers alone", func() {
+		watchedRoute.Annotations[controller.ContributionsAnnotation] = `[{"owner":"ipshield-cr/test-route","ranges":[]}]`
+
+		Expect(defaulter.Default(ctx, watchedRoute)).To(Succeed())
+		Expect(watchedRoute.Annotations).To(HaveKeyWithValue(controller.AllowlistAnnotation, "10.33.52.5"))
+		Expect(watchedRoute.Annotations).NotTo(HaveKey(controller.OriginalAllowlistAnnotation))
+	})
+
+	It("should only admit the contributions injected upon creation", func() {
+		ctx = admission.NewContextWithRequest(ctx, admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				UserInfo:  authenticationv1.UserInfo{Username: "developer"},
+			},
+		})
+		watchedRoute.Annotations[controller.AllowlistAnnotation] = "0.0.0.0/0"
+		watchedRoute.Annotations[controller.ContributionsAnnotation] = `[{"owner":"ipshield-cr/other-namespace","ranges":["0.0.0.0/0"]}]`
+
+		Expect(defaulter.Default(ctx, watchedRoute)).To(Succeed())
+		Expect(watchedRoute.Annotations).To(HaveKeyWithValue(controller.ContributionsAnnotation,
+			`[{"owner":"ipshield-cr/test-route","ranges":["10.100.123.24"]}]`))
+
+		validator := RouteCustomValidator{Enabled: true, Allowlists: defaulter.Reconciler}
+		Expect(validator.ValidateCreate(ctx, watchedRoute)).To(BeEmpty())
+
+		watchedRoute.Annotations[controller.ContributionsAnnotation] = `[{"owner":"ipshield-cr/test-route","ranges":["0.0.0.0/0"]}]`
+		_, err := validator.ValidateCreate(ctx, watchedRoute)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("annotation " + controller.ContributionsAnnotation + " can only be set by IPShield"))
+	})
+})
+
+var _ = Describe("Route Webhook protection", func() {
+	var (
+		ctx       context.Context
+		oldRoute  *route.Route
+		newRoute  *route.Route
+		validator RouteCustomValidator
+	)
+
+	BeforeEach(func() {
+		ctx = admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: "developer", Groups: []string{"system:authenticated"}},
+			},
+		})
+
+		oldRoute = &route.Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-route",
+				Namespace: "default",
+				Labels:    map[string]string{controller.IPShieldWatchedResourceLabel: "true"},
+				Annotations: map[string]string{
+					controller.AllowlistAnnotation: "10.33.52.5 10.100.123.24 10.200.0.0/16",
+					controller.ContributionsAnnotation: `[{"owner":"ipshield-cr/test-route","ranges":["10.100.123.24"]},` +
+						`{"owner":"platform","ranges":["10.200.0.0/16"]}]`,
+				},
+			},
+		}
+		newRoute = oldRoute.DeepCopy()
+		validator = RouteCustomValidator{
+			Enabled:      true,
+			BypassUsers:  []string{"system:serviceaccount:ipshield-operator-system:ipshield-operator-controller-manager"},
+			BypassGroups: []string{"system:masters"},
+		}
+	})
+
+	It("should admit updates keeping the protection", func() {
+		newRoute.Annotations[controller.AllowlistAnnotation] = "10.100.123.24 10.200.0.0/16"
+
+		Expect(validator.ValidateUpdate(ctx, oldRoute, newRoute)).To(BeEmpty())
+	})
+
+	It("should deny the removal of contributed ranges naming the responsible allowlist", func() {
+		newRoute.Annotations[controller.AllowlistAnnotation] = "10.33.52.5 10.200.0.0/16"
+
+		_, err := validator.ValidateUpdate(ctx, oldRoute, newRoute)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("ranges 10.100.123.24 can't be removed from the allowlist, they are required by RouteAllowlist ipshield-cr/test-route"))
+	})
+
+	It("should deny the removal of the enabled label naming every allowlist", func() {
+		delete(newRoute.Labels, controller.IPShieldWatchedResourceLabel)
+
+		_, err := validator.ValidateUpdate(ctx, oldRoute, newRoute)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("protected by RouteAllowlist ipshield-cr/test-route, ClusterRouteAllowlist platform"))
+	})
+
+	It("should admit the removal by bypassed users and groups or when disabled", func() {
+		delete(newRoute.Labels, controller.IPShieldWatchedResourceLabel)
+
+		operatorCtx := admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: validator.BypassUsers[0]},
+			},
+		})
+		Expect(validator.ValidateUpdate(operatorCtx, oldRoute, newRoute)).To(BeEmpty())
+
+		adminCtx := admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: "admin", Groups: []string{"system:masters"}},
+			},
+		})
+		Expect(validator.ValidateUpdate(adminCtx, oldRoute, newRoute)).To(BeEmpty())
+
+		validator.Enabled = false
+		Expect(validator.ValidateUpdate(ctx, oldRoute, newRoute)).To(BeEmpty())
+	})
+
+	It("should only admit changes of the contributions by the operator", func() {
+		newRoute.Annotations[controller.ContributionsAnnotation] = `[{"owner":"platform","ranges":["0.0.0.0/0"]}]`
+		newRoute.Annotations[controller.AllowlistAnnotation] = "0.0.0.0/0"
+
+		_, err := validator.ValidateUpdate(ctx, oldRoute, newRoute)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("annotation " + controller.ContributionsAnnotation + " can only be set by IPShield"))
+
+		adminCtx := admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: "admin", Groups: []string{"system:masters"}},
+			},
+		})
+		_, err = validator.ValidateUpdate(adminCtx, oldRoute, newRoute)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+
+		delete(newRoute.Annotations, controller.ContributionsAnnotation)
+		_, err = validator.ValidateUpdate(ctx, oldRoute, newRoute)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+
+		operatorCtx := admission.NewContextWithRequest(context.Background(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: validator.BypassUsers[0]},
+			},
+		})
+		Expect(validator.ValidateUpdate(operatorCtx, oldRoute, newRoute)).To(BeEmpty())
+	})
+
+	It("should tell policy violations from failures to evaluate the policies", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(networkingv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		policy := &networkingv1alpha1.IPShieldPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "deny-all"},
+			Spec: networkingv1alpha1.IPShieldPolicySpec{
+				NamespaceSelector: &metav1.LabelSelector{},
+				EnforcementAction: networkingv1alpha1.EnforcementActionDeny,
+			},
+		}
+		fakeClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(policy).Build()
+		validator.Policies = &controller.IPShieldPolicyReconciler{Client: fakeClient, Scheme: scheme}
+		validator.Enabled = false
+		delete(newRoute.Labels, controller.IPShieldWatchedResourceLabel)
+
+		_, err := validator.ValidateCreate(ctx, newRoute)
+		Expect(apierrors.IsInternalError(err)).To(BeTrue())
+
+		Expect(fakeClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}})).To(Succeed())
+		_, err = validator.ValidateCreate(ctx, newRoute)
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("the route violates IPShieldPolicy deny-all"))
+	})
+
+	It("should admit any update of routes not protected by IPShield", func() {
+		delete(oldRoute.Annotations, controller.ContributionsAnnotation)
+		delete(newRoute.Annotations, controller.ContributionsAnnotation)
+		delete(newRoute.Labels, controller.IPShieldWatchedResourceLabel)
+		delete(newRoute.Annotations, controller.AllowlistAnnotation)
+
+		Expect(validator.ValidateUpdate(ctx, oldRoute, newRoute)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+)
+
+var clusterrouteallowlistlog = logf.Log.WithName("clusterrouteallowlist-resource")
+
+// SetupClusterRouteAllowlistWebhookWithManager registers the webhook for ClusterRouteAllowlist in the manager.
+func SetupClusterRouteAllowlistWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&networkingv1alpha1.ClusterRouteAllowlist{}).
+		WithValidator(&ClusterRouteAllowlistCustomValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-networking-stakater-com-v1alpha1-clusterrouteallowlist,mutating=false,failurePolicy=fail,sideEffects=None,groups=networking.stakater.com,resources=clusterrouteallowlists,verbs=create;update,versions=v1alpha1,name=vclusterrouteallowlist.networking.stakater.com,admissionReviewVersions=v1
+
+// ClusterRouteAllowlistCustomValidator applies the RouteAllowlist validation to ClusterRouteAllowlists.
+type ClusterRouteAllowlistCustomValidator struct{}
+
+var _ webhook.CustomValidator = &ClusterRouteAllowlistCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ClusterRouteAllowlist.
+func (v *ClusterRouteAllowlistCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	allowlist, ok := obj.(*networkingv1alpha1.ClusterRouteAllowlist)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterRouteAllowlist object but got %T", obj)
+	}
+	clusterrouteallowlistlog.Info("Validation for ClusterRouteAllowlist upon creation", "name", allowlist.GetName())
+
+	return getRouteAllowlistSpecWarnings(&allowlist.Spec, field.NewPath("spec")), validateClusterRouteAllowlist(allowlist)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ClusterRouteAllowlist.
+func (v *ClusterRouteAllowlistCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	allowlist, ok := newObj.(*networkingv1alpha1.ClusterRouteAllowlist)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterRouteAllowlist object for the newObj but got %T", newObj)
+	}
+	clusterrouteallowlistlog.Info("Validation for ClusterRouteAllowlist upon update", "name", allowlist.GetName())
+
+	// Objects being deleted are still updated to remove the finalizer
+	if allowlist.DeletionTimestamp != nil {
+		return nil, nil
+	}
+
+	return getRouteAllowlistSpecWarnings(&allowlist.Spec, field.NewPath("spec")), validateClusterRouteAllowlist(allowlist)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ClusterRouteAllowlist.
+func (v *ClusterRouteAllowlistCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateClusterRouteAllowlist(allowlist *networkingv1alpha1.ClusterRouteAllowlist) error {
+	allErrs := validateRouteAllowlistSpec(&allowlist.Spec, field.NewPath("spec"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(networkingv1alpha1.GroupVersion.WithKind("ClusterRouteAllowlist").GroupKind(), allowlist.Name, allErrs)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+)
+
+var ipsetlog = logf.Log.WithName("ipset-resource")
+
+// SetupIPSetWebhookWithManager registers the webhook for IPSet in the manager.
+func SetupIPSetWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&networkingv1alpha1.IPSet{}).
+		WithValidator(&IPSetCustomValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-networking-stakater-com-v1alpha1-ipset,mutating=false,failurePolicy=fail,sideEffects=None,groups=networking.stakater.com,resources=ipsets,verbs=create;update,versions=v1alpha1,name=vipset.networking.stakater.com,admissionReviewVersions=v1
+
+// IPSetCustomValidator rejects IPSets with invalid IP ranges.
+type IPSetCustomValidator struct{}
+
+var _ webhook.CustomValidator = &IPSetCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type IPSet.
+func (v *IPSetCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	ipSet, ok := obj.(*networkingv1alpha1.IPSet)
+	if !ok {
+		return nil, fmt.Errorf("expected an IPSet object but got %T", obj)
+	}
+	ipsetlog.Info("Validation for IPSet upon creation", "name", ipSet.GetName())
+
+	return getIPSetWarnings(ipSet), validateIPSet(ipSet)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type IPSet.
+func (v *IPSetCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	ipSet, ok := newObj.(*networkingv1alpha1.IPSet)
+	if !ok {
+		return nil, fmt.Errorf("expected an IPSet object for the newObj but got %T", newObj)
+	}
+	ipsetlog.Info("Validation for IPSet upon update", "name", ipSet.GetName())
+
+	return getIPSetWarnings(ipSet), validateIPSet(ipSet)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type IPSet.
+func (v *IPSetCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateIPSet(ipSet *networkingv1alpha1.IPSet) error {
+	var allErrs field.ErrorList
+	path := field.NewPath("spec", "ranges")
+
+	names := map[string]bool{}
+	for i, ipSetRange := range ipSet.Spec.Ranges {
+		if ipSetRange.Name == "" {
+			allErrs = append(allErrs, field.Required(path.Index(i).Child("name"), "ranges must be named"))
+		} else if names[ipSetRange.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Index(i).Child("name"), ipSetRange.Name))
+		}
+		names[ipSetRange.Name] = true
+
+		allErrs = append(allErrs, validateIPRange(ipSetRange.CIDR, path.Index(i).Child("cidr"))...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(networkingv1alpha1.GroupVersion.WithKind("IPSet").GroupKind(), ipSet.Name, allErrs)
+}
+
+func getIPSetWarnings(ipSet *networkingv1alpha1.IPSet) admission.Warnings {
+	var warnings admission.Warnings
+	path := field.NewPath("spec", "ranges")
+
+	for i, ipSetRange := range ipSet.Spec.Ranges {
+		if warning := getIPRangeWarning(ipSetRange.CIDR, path.Index(i).Child("cidr")); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+)
+
+var _ = Describe("IPSet Webhook", func() {
+	var (
+		ctx       context.Context
+		ipSet     *networkingv1alpha1.IPSet
+		validator IPSetCustomValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ipSet = &networkingv1alpha1.IPSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "office", Namespace: "ipshield-cr"},
+			Spec: networkingv1alpha1.IPSetSpec{
+				Ranges: []networkingv1alpha1.IPSetRange{
+					{Name: "head-office", CIDR: "10.20.0.0/24", Description: "Head office"},
+					{Name: "vpn", CIDR: "2001:db8::/32"},
+				},
+			},
+		}
+		validator = IPSetCustomValidator{}
+	})
+
+	It("should admit a valid IPSet", func() {
+		Expect(validator.ValidateCreate(ctx, ipSet)).To(BeEmpty())
+		Expect(validator.ValidateUpdate(ctx, ipSet, ipSet)).To(BeEmpty())
+	})
+
+	It("should deny invalid, unnamed and duplicate ranges", func() {
+		ipSet.Spec.Ranges = append(ipSet.Spec.Ranges,
+			networkingv1alpha1.IPSetRange{Name: "vpn", CIDR: "10.0.0.0/33"},
+			networkingv1alpha1.IPSetRange{CIDR: "10.0.0.1"},
+		)
+
+		_, err := validator.ValidateCreate(ctx, ipSet)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring(`spec.ranges[2].name: Duplicate value: "vpn"`))
+		Expect(err.Error()).To(ContainSubstring(`spec.ranges[2].cidr: Invalid value: "10.0.0.0/33"`))
+		Expect(err.Error()).To(ContainSubstring("spec.ranges[3].name: Required value"))
+	})
+
+	It("should warn about ranges with host bits set", func() {
+		ipSet.Spec.Ranges[0].CIDR = "10.20.0.1/24"
+
+		Expect(validator.ValidateCreate(ctx, ipSet)).To(ConsistOf(
+			`spec.ranges[0].cidr: "10.20.0.1/24" has host bits set and will be applied as 10.20.0.0/24`,
+		))
+	})
+})
@@ -0,0 +1,210 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+	"github.com/stakater/ipshield-operator/pkg/cidr"
+)
+
+var routeallowlistlog = logf.Log.WithName("routeallowlist-resource")
+
+// SetupRouteAllowlistWebhookWithManager registers the webhook for RouteAllowlist in the manager.
+func SetupRouteAllowlistWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&networkingv1alpha1.RouteAllowlist{}).
+		WithValidator(&RouteAllowlistCustomValidator{}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-networking-stakater-com-v1alpha1-routeallowlist,mutating=false,failurePolicy=fail,sideEffects=None,groups=networking.stakater.com,resources=routeallowlists,verbs=create;update,versions=v1alpha1,name=vrouteallowlist.networking.stakater.com,admissionReviewVersions=v1
+
+// RouteAllowlistCustomValidator rejects RouteAllowlists with invalid IP ranges or without a label selector
+// so that they are never written to the route annotations.
+type RouteAllowlistCustomValidator struct{}
+
+var _ webhook.CustomValidator = &RouteAllowlistCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type RouteAllowlist.
+func (v *RouteAllowlistCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	allowlist, ok := obj.(*networkingv1alpha1.RouteAllowlist)
+	if !ok {
+		return nil, fmt.Errorf("expected a RouteAllowlist object but got %T", obj)
+	}
+	routeallowlistlog.Info("Validation for RouteAllowlist upon creation", "name", allowlist.GetName())
+
+	return getRouteAllowlistSpecWarnings(&allowlist.Spec, field.NewPath("spec")), validateRouteAllowlist(allowlist)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type RouteAllowlist.
+func (v *RouteAllowlistCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	allowlist, ok := newObj.(*networkingv1alpha1.RouteAllowlist)
+	if !ok {
+		return nil, fmt.Errorf("expected a RouteAllowlist object for the newObj but got %T", newObj)
+	}
+	routeallowlistlog.Info("Validation for RouteAllowlist upon update", "name", allowlist.GetName())
+
+	// Objects being deleted are still updated to remove the finalizer
+	if allowlist.DeletionTimestamp != nil {
+		return nil, nil
+	}
+
+	return getRouteAllowlistSpecWarnings(&allowlist.Spec, field.NewPath("spec")), validateRouteAllowlist(allowlist)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type RouteAllowlist.
+func (v *RouteAllowlistCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateRouteAllowlist(allowlist *networkingv1alpha1.RouteAllowlist) error {
+	allErrs := validateRouteAllowlistSpec(&allowlist.Spec, field.NewPath("spec"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(networkingv1alpha1.GroupVersion.WithKind("RouteAllowlist").GroupKind(), allowlist.Name, allErrs)
+}
+
+func validateRouteAllowlistSpec(spec *networkingv1alpha1.RouteAllowlistSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateLabelSelector(spec.LabelSelector, path.Child("labelSelector"))...)
+	allErrs = append(allErrs, validateIPRanges(spec.IPRanges, path.Child("ipRanges"))...)
+	allErrs = append(allErrs, validateIPSetRefs(spec.IPSetRefs, path.Child("ipSetRefs"))...)
+	allErrs = append(allErrs, validateNamespaces(spec.NamespaceSelector, spec.Namespaces, path)...)
+	allErrs = append(allErrs, validateEntries(spec.Entries, path.Child("entries"))...)
+
+	return allErrs
+}
+
+func validateEntries(entries []networkingv1alpha1.IPRangeEntry, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, entry := range entries {
+		allErrs = append(allErrs, validateIPRange(entry.CIDR, path.Index(i).Child("cidr"))...)
+
+		if entry.NotBefore != nil && entry.ExpiresAt != nil && !entry.ExpiresAt.After(entry.NotBefore.Time) {
+			allErrs = append(allErrs, field.Invalid(path.Index(i).Child("expiresAt"), entry.ExpiresAt, "must be after notBefore"))
+		}
+	}
+	return allErrs
+}
+
+func validateNamespaces(selector *metav1.LabelSelector, namespaces []string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("namespaceSelector"), selector, err.Error()))
+		}
+	}
+
+	for i, namespace := range namespaces {
+		for _, msg := range validation.IsDNS1123Label(namespace) {
+			allErrs = append(allErrs, field.Invalid(path.Child("namespaces").Index(i), namespace, msg))
+		}
+	}
+	return allErrs
+}
+
+func validateIPSetRefs(refs []string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, ref := range refs {
+		for _, msg := range validation.IsDNS1123Subdomain(ref) {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), ref, msg))
+		}
+	}
+	return allErrs
+}
+
+func validateLabelSelector(selector *metav1.LabelSelector, path *field.Path) field.ErrorList {
+	if selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0) {
+		return field.ErrorList{field.Required(path, "a label selector is required, an empty selector would match every route")}
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		return field.ErrorList{field.Invalid(path, selector, err.Error())}
+	}
+	return nil
+}
+
+func validateIPRanges(ipRanges []string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, ipRange := range ipRanges {
+		allErrs = append(allErrs, validateIPRange(ipRange, path.Index(i))...)
+	}
+	return allErrs
+}
+
+func validateIPRange(ipRange string, path *field.Path) field.ErrorList {
+	if _, err := cidr.Parse(ipRange); err != nil {
+		return field.ErrorList{field.Invalid(path, ipRange, err.Error())}
+	}
+	return nil
+}
+
+func getRouteAllowlistSpecWarnings(spec *networkingv1alpha1.RouteAllowlistSpec, path *field.Path) admission.Warnings {
+	warnings := getIPRangeWarnings(spec.IPRanges, path.Child("ipRanges"))
+
+	now := time.Now()
+	for i, entry := range spec.Entries {
+		entryPath := path.Child("entries").Index(i)
+		if warning := getIPRangeWarning(entry.CIDR, entryPath.Child("cidr")); warning != "" {
+			warnings = append(warnings, warning)
+		}
+		if entry.ExpiresAt != nil && !entry.ExpiresAt.After(now) {
+			warnings = append(warnings, fmt.Sprintf("%s: %q has already expired and will not be applied", entryPath.Child("expiresAt"), entry.CIDR))
+		}
+	}
+	return warnings
+}
+
+// getIPRangeWarnings warns about ranges with host bits set, as they are applied as the network they belong to
+func getIPRangeWarnings(ipRanges []string, path *field.Path) admission.Warnings {
+	var warnings admission.Warnings
+
+	for i, ipRange := range ipRanges {
+		if warning := getIPRangeWarning(ipRange, path.Index(i)); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
+
+func getIPRangeWarning(ipRange string, path *field.Path) string {
+	prefix, err := cidr.Parse(ipRange)
+	if err == nil && prefix != prefix.Masked() {
+		return fmt.Sprintf("%s: %q has host bits set and will be applied as %s", path, ipRange, cidr.Format(prefix))
+	}
+	return ""
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+	"github.com/stakater/ipshield-operator/test/utils"
+)
+
+var _ = Describe("RouteAllowlist Webhook", func() {
+	var (
+		ctx       context.Context
+		allowlist *networkingv1alpha1.RouteAllowlist
+		validator RouteAllowlistCustomValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		allowlist = utils.GetRouteAllowlistSpec("test-route", "ipshield-cr", []string{"10.100.123.24", "192.168.0.0/16"})
+		validator = RouteAllowlistCustomValidator{}
+	})
+
+	It("should admit a valid allowlist", func() {
+		Expect(validator.ValidateCreate(ctx, allowlist)).Error().NotTo(HaveOccurred())
+		Expect(validator.ValidateUpdate(ctx, allowlist, allowlist)).Error().NotTo(HaveOccurred())
+	})
+
+	It("should deny invalid IP ranges", func() {
+		allowlist.Spec.IPRanges = []string{"10.0.0.0/33", "1.2.3", "10.0.0.1"}
+
+		_, err := validator.ValidateCreate(ctx, allowlist)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.ipRanges[0]"))
+		Expect(err.Error()).To(ContainSubstring(`"10.0.0.0/33" is not a valid CIDR range`))
+		Expect(err.Error()).To(ContainSubstring("spec.ipRanges[1]"))
+		Expect(err.Error()).To(ContainSubstring(`"1.2.3" is not a valid IP address`))
+		Expect(err.Error()).NotTo(ContainSubstring("spec.ipRanges[2]"))
+	})
+
+	It("should admit IPv6 ranges", func() {
+		allowlist.Spec.IPRanges = []string{"2001:db8::1", "2001:db8:1::/48"}
+
+		Expect(validator.ValidateCreate(ctx, allowlist)).To(BeEmpty())
+	})
+
+	It("should warn about ranges with host bits set", func() {
+		allowlist.Spec.IPRanges = []string{"10.0.0.1/24", "2001:db8::1/32", "10.0.0.1/32"}
+
+		warnings, err := validator.ValidateCreate(ctx, allowlist)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(
+			`spec.ipRanges[0]: "10.0.0.1/24" has host bits set and will be applied as 10.0.0.0/24`,
+			`spec.ipRanges[1]: "2001:db8::1/32" has host bits set and will be applied as 2001:db8::/32`,
+		))
+	})
+
+	It("should deny a missing or empty label selector", func() {
+		allowlist.Spec.LabelSelector = nil
+		Expect(validator.ValidateCreate(ctx, allowlist)).Error().To(MatchError(ContainSubstring("spec.labelSelector: Required value")))
+
+		allowlist.Spec.LabelSelector = &metav1.LabelSelector{}
+		Expect(validator.ValidateUpdate(ctx, allowlist, allowlist)).Error().To(MatchError(ContainSubstring("spec.labelSelector: Required value")))
+	})
+
+	It("should admit updates to an allowlist being deleted", func() {
+		allowlist.Spec.IPRanges = []string{"invalid"}
+		allowlist.DeletionTimestamp = &metav1.Time{}
+
+		Expect(validator.ValidateUpdate(ctx, allowlist, allowlist)).Error().NotTo(HaveOccurred())
+	})
+
+	It("should deny invalid IPSet references", func() {
+		allowlist.Spec.IPSetRefs = []string{"office", "Not_Valid"}
+
+		_, err := validator.ValidateCreate(ctx, allowlist)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.ipSetRefs[1]"))
+		Expect(err.Error()).NotTo(ContainSubstring("spec.ipSetRefs[0]"))
+	})
+
+	It("should apply the same validation to cluster allowlists", func() {
+		clusterAllowlist := &networkingv1alpha1.ClusterRouteAllowlist{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+			Spec:       allowlist.Spec,
+		}
+		clusterValidator := ClusterRouteAllowlistCustomValidator{}
+
+		Expect(clusterValidator.ValidateCreate(ctx, clusterAllowlist)).To(BeEmpty())
+
+		clusterAllowlist.Spec.IPRanges = []string{"10.0.0.0/33"}
+		clusterAllowlist.Spec.LabelSelector = nil
+
+		_, err := clusterValidator.ValidateUpdate(ctx, clusterAllowlist, clusterAllowlist)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring(`ClusterRouteAllowlist.networking.stakater.com "platform" is invalid`))
+		Expect(err.Error()).To(ContainSubstring("spec.ipRanges[0]"))
+		Expect(err.Error()).To(ContainSubstring("spec.labelSelector: Required value"))
+	})
+
+	It("should deny invalid namespace scoping", func() {
+		allowlist.Spec.NamespaceSelector = &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Unknown"}},
+		}
+		allowlist.Spec.Namespaces = []string{"prod", "Not_Valid"}
+
+		_, err := validator.ValidateCreate(ctx, allowlist)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.namespaceSelector"))
+		Expect(err.Error()).To(ContainSubstring("spec.namespaces[1]"))
+		Expect(err.Error()).NotTo(ContainSubstring("spec.namespaces[0]"))
+	})
+
+	It("should validate time-limited entries", func() {
+		now := time.Now()
+		allowlist.Spec.Entries = []networkingv1alpha1.IPRangeEntry{
+			{CIDR: "10.40.0.0/24", ExpiresAt: &metav1.Time{Time: now.Add(time.Hour)}},
+			{CIDR: "10.50.0.1/24", ExpiresAt: &metav1.Time{Time: now.Add(-time.Hour)}},
+		}
+
+		Expect(validator.ValidateCreate(ctx, allowlist)).To(ConsistOf(
+			`spec.entries[1].cidr: "10.50.0.1/24" has host bits set and will be applied as 10.50.0.0/24`,
+			`spec.entries[1].expiresAt: "10.50.0.1/24" has already expired and will not be applied`,
+		))
+
+		allowlist.Spec.Entries = []networkingv1alpha1.IPRangeEntry{
+			{CIDR: "10.40.0.0/33"},
+			{CIDR: "10.50.0.0/24", NotBefore: &metav1.Time{Time: now.Add(time.Hour)}, ExpiresAt: &metav1.Time{Time: now}},
+		}
+
+		_, err := validator.ValidateCreate(ctx, allowlist)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.entries[0].cidr"))
+		Expect(err.Error()).To(ContainSubstring("spec.entries[1].expiresAt"))
+	})
+})
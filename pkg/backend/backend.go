@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backend defines the targets RouteAllowlists and ClusterRouteAllowlists are enforced on. The reconcilers
+// record the ranges each allowlist contributed in the annotations of the targets and back up their original
+// allowlists, a backend only reads and writes the allowlist of its kind of targets.
+package backend
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+)
+
+// Backend enforces allowlists on a kind of targets. Allowlists are passed and returned as canonical ranges,
+// see cidr.Merge.
+type Backend interface {
+	// Kind is the kind of the targets, as listed in spec.targets and reported in status.routes
+	Kind() networkingv1alpha1.TargetKind
+	// ConfigMapName is the name of the config map of the watch namespace the original allowlists are backed up in
+	ConfigMapName() string
+	// NewObject returns an empty target, the reconcilers get and watch targets through it
+	NewObject() client.Object
+	// List returns the targets of every namespace matching the selector
+	List(ctx context.Context, c client.Client, selector labels.Selector) ([]client.Object, error)
+	// GetAllowlist returns the ranges currently allowed on the target
+	GetAllowlist(ctx context.Context, c client.Client, target client.Object) ([]string, error)
+	// Apply allows the ranges on the target on behalf of owner, the RouteAllowlist or ClusterRouteAllowlist being
+	// reconciled. The target may be updated in memory, the reconcilers patch it afterwards. An empty allowlist
+	// accepts every client, as a route without allowlist does, so objects generated to filter clients are removed
+	// rather than left denying them all.
+	Apply(ctx context.Context, c client.Client, owner, target client.Object, allowlist []string) error
+	// Restore writes back the original allowlist of the target once no allowlist manages it anymore. The target
+	// may be updated in memory, the reconcilers patch it afterwards.
+	Restore(ctx context.Context, c client.Client, owner, target client.Object, original []string) error
+	// GetHost returns the host served by the target, as reported in the status
+	GetHost(target client.Object) string
+}
+
+// APIDependent is implemented by backends relying on APIs that may not be installed in the cluster
+type APIDependent interface {
+	// RequiredKinds are the kinds that must be served for the backend to work
+	RequiredKinds() []schema.GroupVersionKind
+}
+
+// Available returns the backends whose required kinds are all served, according to mapper
+func Available(mapper meta.RESTMapper, backends ...Backend) []Backend {
+	return slices.DeleteFunc(backends, func(b Backend) bool {
+		dependent, ok := b.(APIDependent)
+		if !ok {
+			return false
+		}
+		return slices.ContainsFunc(dependent.RequiredKinds(), func(gvk schema.GroupVersionKind) bool {
+			_, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+			return err != nil
+		})
+	})
+}
+
+// Defaults returns the backends of the targets supported out of the box, routes have their allowlist written
+// to routeAnnotation
+func Defaults(routeAnnotation string) []Backend {
+	return []Backend{&Route{Annotation: routeAnnotation}, &Ingress{}}
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []Backend
+)
+
+// Register adds a backend to the ones the operator enforces allowlists through, next to the built-in ones.
+// Modules providing backends call it from an init function, so importing them in the main package of the
+// operator is enough. It panics if the backend is nil or a backend of the same kind is already registered.
+func Register(b Backend) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if b == nil {
+		panic("backend: Register backend is nil")
+	}
+	if slices.ContainsFunc(registry, func(r Backend) bool { return r.Kind() == b.Kind() }) {
+		panic(fmt.Sprintf("backend: Register called twice for kind %s", b.Kind()))
+	}
+	registry = append(registry, b)
+}
+
+// Registered returns the backends added through Register, in the order they were registered
+func Registered() []Backend {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	return slices.Clone(registry)
+}
+
+func getAnnotations(obj client.Object) map[string]string {
+	if obj.GetAnnotations() == nil {
+		obj.SetAnnotations(make(map[string]string))
+	}
+	return obj.GetAnnotations()
+}
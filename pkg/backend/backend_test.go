@@ -0,0 +1,44 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+)
+
+// inHouse stands for a backend provided by another module
+type inHouse struct {
+	Ingress
+}
+
+func (*inHouse) Kind() networkingv1alpha1.TargetKind { return "InHouse" }
+
+var _ = Describe("Backend registry", func() {
+
+	It("returns the registered backends and rejects duplicate kinds", func() {
+		b := &inHouse{}
+		Register(b)
+		Expect(Registered()).To(ConsistOf(b))
+
+		Expect(func() { Register(&inHouse{}) }).To(PanicWith("backend: Register called twice for kind InHouse"))
+		Expect(func() { Register(nil) }).To(Panic())
+		Expect(Registered()).To(HaveLen(1))
+	})
+})
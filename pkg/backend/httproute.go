@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+	"github.com/stakater/ipshield-operator/pkg/cidr"
+)
+
+const (
+	WatchedHTTPRoutesConfigMapName = "watched-httproutes"
+
+	// SecurityPolicyPrefix prefixes the name of the SecurityPolicy generated for an HTTPRoute
+	SecurityPolicyPrefix = "ipshield-"
+
+	securityPolicyRuleName = "ipshield-allowlist"
+)
+
+var (
+	HTTPRouteGVK      = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+	SecurityPolicyGVK = schema.GroupVersionKind{Group: "gateway.envoyproxy.io", Version: "v1alpha1", Kind: "SecurityPolicy"}
+)
+
+// HTTPRoute enforces allowlists on Gateway API HTTPRoutes through an Envoy Gateway SecurityPolicy generated for
+// each route, denying the clients outside of the allowlist. The policy is owned by the route so it is garbage
+// collected along with it, and deleted once no allowlist manages the route.
+type HTTPRoute struct{}
+
+var _ Backend = &HTTPRoute{}
+var _ APIDependent = &HTTPRoute{}
+
+func (b *HTTPRoute) Kind() networkingv1alpha1.TargetKind {
+	return networkingv1alpha1.TargetHTTPRoute
+}
+
+func (b *HTTPRoute) ConfigMapName() string {
+	return WatchedHTTPRoutesConfigMapName
+}
+
+func (b *HTTPRoute) RequiredKinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{HTTPRouteGVK, SecurityPolicyGVK}
+}
+
+func (b *HTTPRoute) NewObject() client.Object {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(HTTPRouteGVK)
+	return obj
+}
+
+func (b *HTTPRoute) List(ctx context.Context, c client.Client, selector labels.Selector) ([]client.Object, error) {
+	return listUnstructured(ctx, c, HTTPRouteGVK, selector)
+}
+
+func (b *HTTPRoute) GetAllowlist(ctx context.Context, c client.Client, target client.Object) ([]string, error) {
+	policy, err := getManaged(ctx, c, SecurityPolicyGVK, getPolicyKey(target))
+	if err != nil || policy == nil {
+		return nil, err
+	}
+
+	rules, _, err := unstructured.NestedSlice(policy.Object, "spec", "authorization", "rules")
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		rule, ok := rule.(map[string]interface{})
+		if !ok || rule["name"] != securityPolicyRuleName {
+			continue
+		}
+		clientCIDRs, _, err := unstructured.NestedStringSlice(rule, "principal", "clientCIDRs")
+		return formatRanges(clientCIDRs), err
+	}
+	return nil, nil
+}
+
+// Apply creates or updates the SecurityPolicy of the route, the policy is deleted when the allowlist is empty
+func (b *HTTPRoute) Apply(ctx context.Context, c client.Client, _, target client.Object, allowlist []string) error {
+	if len(allowlist) == 0 {
+		return deleteManaged(ctx, c, SecurityPolicyGVK, getPolicyKey(target))
+	}
+
+	// Envoy Gateway only accepts ranges with a prefix length
+	clientCIDRs := make([]interface{}, len(allowlist))
+	for i, value := range allowlist {
+		prefix, err := cidr.Parse(value)
+		if err != nil {
+			return err
+		}
+		clientCIDRs[i] = prefix.String()
+	}
+
+	spec := map[string]interface{}{
+		"targetRefs": []interface{}{
+			map[string]interface{}{
+				"group": HTTPRouteGVK.Group,
+				"kind":  HTTPRouteGVK.Kind,
+				"name":  target.GetName(),
+			},
+		},
+		"authorization": map[string]interface{}{
+			"defaultAction": "Deny",
+			"rules": []interface{}{
+				map[string]interface{}{
+					"name":      securityPolicyRuleName,
+					"action":    "Allow",
+					"principal": map[string]interface{}{"clientCIDRs": clientCIDRs},
+				},
+			},
+		},
+	}
+	return createOrUpdateManaged(ctx, c, SecurityPolicyGVK, getPolicyKey(target), target, spec)
+}
+
+func (b *HTTPRoute) Restore(ctx context.Context, c client.Client, owner, target client.Object, original []string) error {
+	return b.Apply(ctx, c, owner, target, original)
+}
+
+// GetHost returns the first hostname of the route, HTTPRoutes may serve several hostnames
+func (b *HTTPRoute) GetHost(target client.Object) string {
+	hostnames, _, _ := unstructured.NestedStringSlice(target.(*unstructured.Unstructured).Object, "spec", "hostnames")
+	if len(hostnames) == 0 {
+		return ""
+	}
+	return hostnames[0]
+}
+
+func getPolicyKey(target client.Object) client.ObjectKey {
+	return client.ObjectKey{Namespace: target.GetNamespace(), Name: SecurityPolicyPrefix + target.GetName()}
+}
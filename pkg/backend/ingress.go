@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+)
+
+const (
+	// IngressAllowlistAnnotation is the annotation ingress-nginx reads the allowlist of an ingress from
+	IngressAllowlistAnnotation = "nginx.ingress.kubernetes.io/whitelist-source-range"
+
+	WatchedIngressesConfigMapName = "watched-ingresses"
+)
+
+// Ingress enforces allowlists on Kubernetes Ingresses through the annotation of ingress-nginx, ranges are
+// separated by commas
+type Ingress struct{}
+
+var _ Backend = &Ingress{}
+
+func (b *Ingress) Kind() networkingv1alpha1.TargetKind {
+	return networkingv1alpha1.TargetIngress
+}
+
+func (b *Ingress) ConfigMapName() string {
+	return WatchedIngressesConfigMapName
+}
+
+func (b *Ingress) NewObject() client.Object {
+	return &networkingv1.Ingress{}
+}
+
+func (b *Ingress) List(ctx context.Context, c client.Client, selector labels.Selector) ([]client.Object, error) {
+	ingresses := &networkingv1.IngressList{}
+	if err := c.List(ctx, ingresses, &client.ListOptions{LabelSelector: selector}); err != nil {
+		return nil, err
+	}
+
+	result := make([]client.Object, len(ingresses.Items))
+	for i := range ingresses.Items {
+		result[i] = &ingresses.Items[i]
+	}
+	return result, nil
+}
+
+func (b *Ingress) GetAllowlist(_ context.Context, _ client.Client, target client.Object) ([]string, error) {
+	return strings.FieldsFunc(target.GetAnnotations()[IngressAllowlistAnnotation], func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}), nil
+}
+
+func (b *Ingress) Apply(_ context.Context, _ client.Client, _, target client.Object, allowlist []string) error {
+	annotations := getAnnotations(target)
+	delete(annotations, IngressAllowlistAnnotation)
+
+	if len(allowlist) > 0 {
+		annotations[IngressAllowlistAnnotation] = strings.Join(allowlist, ",")
+	}
+	return nil
+}
+
+func (b *Ingress) Restore(ctx context.Context, c client.Client, owner, target client.Object, original []string) error {
+	return b.Apply(ctx, c, owner, target, original)
+}
+
+// GetHost returns the first host of the rules, ingresses may serve several hosts
+func (b *Ingress) GetHost(target client.Object) string {
+	for _, rule := range target.(*networkingv1.Ingress).Spec.Rules {
+		if rule.Host != "" {
+			return rule.Host
+		}
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/stakater/ipshield-operator/pkg/cidr"
+)
+
+const (
+	// ManagedByLabel marks the objects generated by IPShield, objects without it are never updated nor deleted
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	ManagedByValue = "ipshield-operator"
+)
+
+func listUnstructured(ctx context.Context, c client.Client, gvk schema.GroupVersionKind, selector labels.Selector) ([]client.Object, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := c.List(ctx, list, &client.ListOptions{LabelSelector: selector}); err != nil {
+		return nil, err
+	}
+
+	result := make([]client.Object, len(list.Items))
+	for i := range list.Items {
+		result[i] = &list.Items[i]
+	}
+	return result, nil
+}
+
+// getManaged returns the object generated by IPShield, nil if it doesn't exist or isn't managed by IPShield
+func getManaged(ctx context.Context, c client.Client, gvk schema.GroupVersionKind, key client.ObjectKey) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := c.Get(ctx, key, obj); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if obj.GetLabels()[ManagedByLabel] != ManagedByValue {
+		return nil, nil
+	}
+	return obj, nil
+}
+
+// createOrUpdateManaged writes the spec of the object generated by IPShield for the target, the object is owned
+// by the target. Objects with the same name that aren't managed by IPShield are left untouched.
+func createOrUpdateManaged(ctx context.Context, c client.Client, gvk schema.GroupVersionKind, key client.ObjectKey,
+	target client.Object, spec map[string]interface{}) error {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(key.Namespace)
+	obj.SetName(key.Name)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, c, obj, func() error {
+		if obj.GetResourceVersion() != "" && obj.GetLabels()[ManagedByLabel] != ManagedByValue {
+			return fmt.Errorf("%s %s already exists and isn't managed by IPShield", gvk.Kind, key)
+		}
+
+		objLabels := obj.GetLabels()
+		if objLabels == nil {
+			objLabels = make(map[string]string)
+		}
+		objLabels[ManagedByLabel] = ManagedByValue
+		obj.SetLabels(objLabels)
+
+		obj.Object["spec"] = spec
+		return controllerutil.SetOwnerReference(target, obj, c.Scheme())
+	})
+	return err
+}
+
+// deleteManaged deletes the object generated by IPShield, if any
+func deleteManaged(ctx context.Context, c client.Client, gvk schema.GroupVersionKind, key client.ObjectKey) error {
+	obj, err := getManaged(ctx, c, gvk, key)
+	if err != nil || obj == nil {
+		return err
+	}
+
+	if err = c.Delete(ctx, obj); errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// formatRanges returns the ranges read from a generated object in the form of the ranges contributed by the
+// allowlists, single addresses without prefix length
+func formatRanges(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	ranges := make([]string, len(values))
+	for i, value := range values {
+		ranges[i] = value
+		if prefix, err := cidr.Parse(value); err == nil {
+			ranges[i] = cidr.Format(prefix)
+		}
+	}
+	return ranges
+}
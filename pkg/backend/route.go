@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"context"
+	"strings"
+
+	route "github.com/openshift/api/route/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+)
+
+const (
+	AllowlistAnnotation = "haproxy.router.openshift.io/ip_whitelist"
+	// IPAllowlistAnnotation supersedes AllowlistAnnotation on newer OpenShift releases
+	IPAllowlistAnnotation = "haproxy.router.openshift.io/ip_allowlist"
+
+	WatchedRoutesConfigMapName = "watched-routes"
+)
+
+// Route enforces allowlists on OpenShift Routes through the annotation of the HAProxy router
+type Route struct {
+	// Annotation is the annotation the allowlist is written to, AllowlistAnnotation if empty
+	Annotation string
+}
+
+var _ Backend = &Route{}
+
+func (b *Route) Kind() networkingv1alpha1.TargetKind {
+	return networkingv1alpha1.TargetRoute
+}
+
+func (b *Route) ConfigMapName() string {
+	return WatchedRoutesConfigMapName
+}
+
+func (b *Route) NewObject() client.Object {
+	return &route.Route{}
+}
+
+func (b *Route) List(ctx context.Context, c client.Client, selector labels.Selector) ([]client.Object, error) {
+	routes := &route.RouteList{}
+	if err := c.List(ctx, routes, &client.ListOptions{LabelSelector: selector}); err != nil {
+		return nil, err
+	}
+
+	result := make([]client.Object, len(routes.Items))
+	for i := range routes.Items {
+		result[i] = &routes.Items[i]
+	}
+	return result, nil
+}
+
+func (b *Route) GetAllowlist(_ context.Context, _ client.Client, target client.Object) ([]string, error) {
+	return GetRouteAllowlist(target.GetAnnotations()), nil
+}
+
+func (b *Route) Apply(_ context.Context, _ client.Client, _, target client.Object, allowlist []string) error {
+	SetRouteAllowlist(getAnnotations(target), b.getAnnotation(), allowlist)
+	return nil
+}
+
+func (b *Route) Restore(ctx context.Context, c client.Client, owner, target client.Object, original []string) error {
+	return b.Apply(ctx, c, owner, target, original)
+}
+
+func (b *Route) GetHost(target client.Object) string {
+	return target.(*route.Route).Spec.Host
+}
+
+func (b *Route) getAnnotation() string {
+	if b.Annotation == "" {
+		return AllowlistAnnotation
+	}
+	return b.Annotation
+}
+
+// GetRouteAllowlist returns the ranges of both the legacy and the current allowlist annotations
+func GetRouteAllowlist(annotations map[string]string) []string {
+	return append(strings.Fields(annotations[AllowlistAnnotation]), strings.Fields(annotations[IPAllowlistAnnotation])...)
+}
+
+// SetRouteAllowlist sets the allowlist annotation, removing it when no range is left. The other allowlist
+// annotation is removed so routes are migrated to the annotation understood by the router.
+func SetRouteAllowlist(annotations map[string]string, annotation string, allowlist []string) {
+	delete(annotations, AllowlistAnnotation)
+	delete(annotations, IPAllowlistAnnotation)
+
+	if len(allowlist) > 0 {
+		annotations[annotation] = strings.Join(allowlist, " ")
+	}
+}
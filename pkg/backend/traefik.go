@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backend
+
+import (
+	"context"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
+)
+
+const (
+	WatchedIngressRoutesConfigMapName = "watched-ingressroutes"
+
+	// MiddlewarePrefix prefixes the name of the Middleware generated for an IngressRoute
+	MiddlewarePrefix = "ipshield-"
+)
+
+var (
+	IngressRouteGVK = schema.GroupVersionKind{Group: "traefik.io", Version: "v1alpha1", Kind: "IngressRoute"}
+	MiddlewareGVK   = schema.GroupVersionKind{Group: "traefik.io", Version: "v1alpha1", Kind: "Middleware"}
+)
+
+// Traefik enforces allowlists on Traefik IngressRoutes through an ipAllowList Middleware generated for each
+// IngressRoute and attached to each of its routes. The middleware is owned by the IngressRoute, and detached and
+// deleted once no allowlist manages it.
+type Traefik struct{}
+
+var _ Backend = &Traefik{}
+var _ APIDependent = &Traefik{}
+
+func (b *Traefik) Kind() networkingv1alpha1.TargetKind {
+	return networkingv1alpha1.TargetIngressRoute
+}
+
+func (b *Traefik) ConfigMapName() string {
+	return WatchedIngressRoutesConfigMapName
+}
+
+func (b *Traefik) RequiredKinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{IngressRouteGVK, MiddlewareGVK}
+}
+
+func (b *Traefik) NewObject() client.Object {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(IngressRouteGVK)
+	return obj
+}
+
+func (b *Traefik) List(ctx context.Context, c client.Client, selector labels.Selector) ([]client.Object, error) {
+	return listUnstructured(ctx, c, IngressRouteGVK, selector)
+}
+
+func (b *Traefik) GetAllowlist(ctx context.Context, c client.Client, target client.Object) ([]string, error) {
+	middleware, err := getManaged(ctx, c, MiddlewareGVK, getMiddlewareKey(target))
+	if err != nil || middleware == nil {
+		return nil, err
+	}
+
+	sourceRange, _, err := unstructured.NestedStringSlice(middleware.Object, "spec", "ipAllowList", "sourceRange")
+	return formatRanges(sourceRange), err
+}
+
+// Apply creates or updates the Middleware of the IngressRoute and attaches it first to each of its routes. The
+// middleware is detached and deleted when the allowlist is empty.
+func (b *Traefik) Apply(ctx context.Context, c client.Client, _, target client.Object, allowlist []string) error {
+	key := getMiddlewareKey(target)
+	if len(allowlist) == 0 {
+		if err := setMiddlewareAttached(target, key.Name, false); err != nil {
+			return err
+		}
+		return deleteManaged(ctx, c, MiddlewareGVK, key)
+	}
+
+	sourceRange := make([]interface{}, len(allowlist))
+	for i, value := range allowlist {
+		sourceRange[i] = value
+	}
+	spec := map[string]interface{}{
+		"ipAllowList": map[string]interface{}{"sourceRange": sourceRange},
+	}
+	if err := createOrUpdateManaged(ctx, c, MiddlewareGVK, key, target, spec); err != nil {
+		return err
+	}
+	return setMiddlewareAttached(target, key.Name, true)
+}
+
+func (b *Traefik) Restore(ctx context.Context, c client.Client, owner, target client.Object, original []string) error {
+	return b.Apply(ctx, c, owner, target, original)
+}
+
+// GetHost returns the rule of the first route, IngressRoutes match hosts through rules
+func (b *Traefik) GetHost(target client.Object) string {
+	routes, _, _ := unstructured.NestedSlice(target.(*unstructured.Unstructured).Object, "spec", "routes")
+	if len(routes) == 0 {
+		return ""
+	}
+	route, _ := routes[0].(map[string]interface{})
+	match, _ := route["match"].(string)
+	return match
+}
+
+func getMiddlewareKey(target client.Object) client.ObjectKey {
+	return client.ObjectKey{Namespace: target.GetNamespace(), Name: MiddlewarePrefix + target.GetName()}
+}
+
+// setMiddlewareAttached adds the middleware in front of the middlewares of each route of the IngressRoute, or
+// removes it from them. The other middlewares are left untouched.
+func setMiddlewareAttached(target client.Object, name string, attached bool) error {
+	obj := target.(*unstructured.Unstructured).Object
+	routes, found, err := unstructured.NestedSlice(obj, "spec", "routes")
+	if err != nil || !found {
+		return err
+	}
+
+	for i, item := range routes {
+		route, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		middlewares, _ := route["middlewares"].([]interface{})
+		middlewares = slices.DeleteFunc(slices.Clone(middlewares), func(item interface{}) bool {
+			middleware, ok := item.(map[string]interface{})
+			return ok && middleware["name"] == name &&
+				(middleware["namespace"] == nil || middleware["namespace"] == target.GetNamespace())
+		})
+		if attached {
+			middlewares = slices.Insert(middlewares, 0, interface{}(map[string]interface{}{"name": name}))
+		}
+
+		if len(middlewares) == 0 {
+			delete(route, "middlewares")
+		} else {
+			route["middlewares"] = middlewares
+		}
+		routes[i] = route
+	}
+	return unstructured.SetNestedSlice(obj, routes, "spec", "routes")
+}
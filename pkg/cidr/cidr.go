@@ -0,0 +1,205 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cidr parses, normalizes and aggregates the IPv4 and IPv6 addresses and CIDR ranges
+// that make up a route allowlist
+package cidr
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// Parse parses a single IP address or a CIDR range. A single address is returned as a prefix
+// covering only that address.
+func Parse(value string) (netip.Prefix, error) {
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("%q is not a valid CIDR range", value)
+		}
+		return prefix, nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil || addr.Zone() != "" {
+		return netip.Prefix{}, fmt.Errorf("%q is not a valid IP address", value)
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// Normalize returns the canonical form of an IP address or CIDR range, so that textual variants
+// such as 10.0.0.1 and 10.0.0.1/32 or 10.0.0.1/24 and 10.0.0.0/24 compare equal
+func Normalize(value string) (string, error) {
+	prefix, err := Parse(value)
+	if err != nil {
+		return "", err
+	}
+	return Format(prefix), nil
+}
+
+// Format returns the canonical form of a prefix. Prefixes covering a single address are formatted
+// as a plain address.
+func Format(prefix netip.Prefix) string {
+	prefix = prefix.Masked()
+	if prefix.IsSingleIP() {
+		return prefix.Addr().String()
+	}
+	return prefix.String()
+}
+
+// Merge returns the union of the given values in canonical form, collapsing adjacent and contained
+// ranges. IPv4 ranges are sorted before IPv6 ranges. Values that can't be parsed are kept as is and
+// sorted after the ranges.
+func Merge(values ...[]string) []string {
+	var prefixes []netip.Prefix
+	var invalid []string
+
+	for _, list := range values {
+		p, i := parseAll(list)
+		prefixes = append(prefixes, p...)
+		invalid = append(invalid, i...)
+	}
+
+	return format(Aggregate(prefixes), invalid)
+}
+
+// Subtract returns the values with every address covered by remove taken out, in canonical form.
+// Ranges that partially overlap a removed range are split into the remaining prefixes.
+func Subtract(values []string, remove []string) []string {
+	prefixes, invalid := parseAll(values)
+	removedPrefixes, removedInvalid := parseAll(remove)
+
+	for _, removed := range removedPrefixes {
+		var remaining []netip.Prefix
+		for _, prefix := range prefixes {
+			remaining = append(remaining, subtract(prefix.Masked(), removed.Masked())...)
+		}
+		prefixes = remaining
+	}
+
+	invalid = slices.DeleteFunc(invalid, func(s string) bool { return slices.Contains(removedInvalid, s) })
+	return format(Aggregate(prefixes), invalid)
+}
+
+// Aggregate returns the smallest sorted list of prefixes covering the same addresses as the given ones
+func Aggregate(prefixes []netip.Prefix) []netip.Prefix {
+	sorted := make([]netip.Prefix, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		sorted = append(sorted, prefix.Masked())
+	}
+	slices.SortFunc(sorted, comparePrefixes)
+
+	result := make([]netip.Prefix, 0, len(sorted))
+	for _, prefix := range sorted {
+		// Sorting places a prefix right after the prefix containing it, if any
+		if n := len(result); n > 0 && result[n-1].Overlaps(prefix) {
+			continue
+		}
+		result = append(result, prefix)
+
+		// Collapse siblings into their parent until no sibling is left
+		for n := len(result); n > 1; n = len(result) {
+			parent, ok := siblingParent(result[n-2], result[n-1])
+			if !ok {
+				break
+			}
+			result = append(result[:n-2], parent)
+		}
+	}
+	return result
+}
+
+func parseAll(values []string) ([]netip.Prefix, []string) {
+	var prefixes []netip.Prefix
+	var invalid []string
+
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		prefix, err := Parse(value)
+		if err != nil {
+			invalid = append(invalid, value)
+			continue
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, invalid
+}
+
+func format(prefixes []netip.Prefix, invalid []string) []string {
+	result := make([]string, 0, len(prefixes)+len(invalid))
+	for _, prefix := range prefixes {
+		result = append(result, Format(prefix))
+	}
+
+	slices.Sort(invalid)
+	return append(result, slices.Compact(invalid)...)
+}
+
+func comparePrefixes(a, b netip.Prefix) int {
+	if c := a.Addr().Compare(b.Addr()); c != 0 {
+		return c
+	}
+	return a.Bits() - b.Bits()
+}
+
+// siblingParent returns the parent prefix of a and b if they are the two halves of it
+func siblingParent(a, b netip.Prefix) (netip.Prefix, bool) {
+	if a.Bits() != b.Bits() || a.Bits() == 0 || a.Addr().BitLen() != b.Addr().BitLen() || a == b {
+		return netip.Prefix{}, false
+	}
+
+	parentA := netip.PrefixFrom(a.Addr(), a.Bits()-1).Masked()
+	parentB := netip.PrefixFrom(b.Addr(), b.Bits()-1).Masked()
+	return parentA, parentA == parentB
+}
+
+// subtract returns the prefixes covering the addresses of p that are not in q
+func subtract(p, q netip.Prefix) []netip.Prefix {
+	if !p.Overlaps(q) {
+		return []netip.Prefix{p}
+	}
+	if q.Bits() <= p.Bits() {
+		return nil
+	}
+
+	lower, upper := split(p)
+	return append(subtract(lower, q), subtract(upper, q)...)
+}
+
+// split returns the two halves of a prefix
+func split(p netip.Prefix) (netip.Prefix, netip.Prefix) {
+	bits := p.Bits() + 1
+	lower := netip.PrefixFrom(p.Addr(), bits)
+
+	bytes := p.Addr().As16()
+	offset := 0
+	if p.Addr().Is4() {
+		offset = 12
+	}
+	index := offset + p.Bits()/8
+	bytes[index] |= 0x80 >> (p.Bits() % 8)
+
+	addr := netip.AddrFrom16(bytes)
+	if p.Addr().Is4() {
+		addr = addr.Unmap()
+	}
+	return lower, netip.PrefixFrom(addr, bits)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cidr
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CIDR", func() {
+
+	DescribeTable("normalizes addresses and ranges",
+		func(value, expected string) {
+			Expect(Normalize(value)).To(Equal(expected))
+		},
+		Entry("IPv4 address", "10.0.0.1", "10.0.0.1"),
+		Entry("IPv4 host prefix", "10.0.0.1/32", "10.0.0.1"),
+		Entry("IPv4 range with host bits", "10.0.0.1/24", "10.0.0.0/24"),
+		Entry("IPv6 address", "2001:DB8:0:0::1", "2001:db8::1"),
+		Entry("IPv6 host prefix", "2001:db8::1/128", "2001:db8::1"),
+		Entry("IPv6 range with host bits", "2001:db8::1/32", "2001:db8::/32"),
+	)
+
+	DescribeTable("rejects invalid values",
+		func(value string) {
+			Expect(Normalize(value)).Error().To(HaveOccurred())
+		},
+		Entry("prefix too long", "10.0.0.0/33"),
+		Entry("truncated address", "1.2.3"),
+		Entry("IPv6 prefix too long", "2001:db8::/129"),
+		Entry("zoned address", "fe80::1%eth0"),
+		Entry("hostname", "example.com"),
+	)
+
+	DescribeTable("merges and aggregates ranges",
+		func(values []string, expected []string) {
+			Expect(Merge(values)).To(Equal(expected))
+		},
+		Entry("duplicates in different forms", []string{"10.0.0.1", "10.0.0.1/32"}, []string{"10.0.0.1"}),
+		Entry("contained ranges", []string{"10.0.0.5", "10.0.0.0/24", "10.0.0.128/25"}, []string{"10.0.0.0/24"}),
+		Entry("adjacent ranges", []string{"10.0.0.0/25", "10.0.0.128/25", "10.0.1.0/24"}, []string{"10.0.0.0/23"}),
+		Entry("adjacent addresses", []string{"10.0.0.1", "10.0.0.0"}, []string{"10.0.0.0/31"}),
+		Entry("non adjacent ranges", []string{"10.0.1.0/24", "10.0.2.0/24"}, []string{"10.0.1.0/24", "10.0.2.0/24"}),
+		Entry("dual stack", []string{"2001:db8::/33", "10.0.0.1", "2001:db8:8000::/33"}, []string{"10.0.0.1", "2001:db8::/32"}),
+		Entry("invalid values are kept", []string{"10.0.0.1", "", "invalid", "invalid"}, []string{"10.0.0.1", "invalid"}),
+	)
+
+	DescribeTable("subtracts ranges",
+		func(values, remove []string, expected []string) {
+			Expect(Subtract(values, remove)).To(Equal(expected))
+		},
+		Entry("equal address in another form", []string{"10.0.0.1", "10.0.0.2"}, []string{"10.0.0.1/32"}, []string{"10.0.0.2"}),
+		Entry("contained range", []string{"10.0.0.0/25"}, []string{"10.0.0.0/24"}, []string{}),
+		Entry("part of an aggregated range", []string{"10.0.0.0/23"}, []string{"10.0.1.0/24"}, []string{"10.0.0.0/24"}),
+		Entry("single address", []string{"10.0.0.0/30"}, []string{"10.0.0.1"}, []string{"10.0.0.0", "10.0.0.2/31"}),
+		Entry("IPv6 range", []string{"2001:db8::/32", "10.0.0.1"}, []string{"2001:db8:8000::/33"}, []string{"10.0.0.1", "2001:db8::/33"}),
+		Entry("invalid values", []string{"invalid", "other"}, []string{"invalid"}, []string{"other"}),
+	)
+})
@@ -26,6 +26,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
 	route "github.com/openshift/api/route/v1"
 	networkingv1alpha1 "github.com/stakater/ipshield-operator/api/v1alpha1"
 	"github.com/stakater/ipshield-operator/internal/controller"
@@ -58,11 +59,15 @@ const (
 var client kubeclient.Client
 var clientset *kubernetes.Clientset
 
+// allowlistAnnotation is the route annotation the operator writes to on the cluster under test
+var allowlistAnnotation string
+
 var _ = BeforeSuite(func() {
 	scheme := runtime.NewScheme()
 
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(route.AddToScheme(scheme))
+	utilruntime.Must(configv1.AddToScheme(scheme))
 	utilruntime.Must(networkingv1alpha1.AddToScheme(scheme))
 
 	config := ctrl.GetConfigOrDie()
@@ -77,6 +82,9 @@ var _ = BeforeSuite(func() {
 
 	Expect(utils.CreateNamespace(context.TODO(), clientset, OperatorNamespace)).To(Succeed())
 
+	allowlistAnnotation, err = controller.GetAllowlistAnnotation(context.TODO(), client)
+	Expect(err).NotTo(HaveOccurred())
+
 })
 
 var _ = Describe("controller", Ordered, func() {
@@ -230,7 +238,7 @@ var _ = Describe("controller", Ordered, func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(r).NotTo(BeNil())
 			Expect(r.Labels).ShouldNot(HaveKey(controller.IPShieldWatchedResourceLabel))
-			Expect(r.Annotations).ShouldNot(HaveKeyWithValue(controller.AllowlistAnnotation, "10.200.15.13"))
+			Expect(r.Annotations).ShouldNot(HaveKeyWithValue(allowlistAnnotation, "10.200.15.13"))
 		})
 
 		It("Deploy CR and route has label", func() {
@@ -256,7 +264,7 @@ var _ = Describe("controller", Ordered, func() {
 			Expect(r).NotTo(BeNil())
 
 			Expect(r.Labels).Should(HaveKeyWithValue(controller.IPShieldWatchedResourceLabel, strconv.FormatBool(true)))
-			Expect(r.Annotations).Should(HaveKeyWithValue(controller.AllowlistAnnotation, "10.200.15.13"))
+			Expect(r.Annotations).Should(HaveKeyWithValue(allowlistAnnotation, "10.200.15.13"))
 		})
 
 		It("Deploy CR and route already had allowlist 1 element", func() {
@@ -267,7 +275,7 @@ var _ = Describe("controller", Ordered, func() {
 			Expect(r).NotTo(BeNil())
 
 			r.Labels[controller.IPShieldWatchedResourceLabel] = strconv.FormatBool(true)
-			r.Annotations[controller.AllowlistAnnotation] = "192.168.10.32"
+			r.Annotations[allowlistAnnotation] = "192.168.10.32"
 
 			Expect(client.Update(context.TODO(), r)).NotTo(HaveOccurred())
 
@@ -284,8 +292,8 @@ var _ = Describe("controller", Ordered, func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(r).NotTo(BeNil())
 
-			Expect(r.Annotations).Should(HaveKey(controller.AllowlistAnnotation))
-			Expect(strings.Split(r.Annotations[controller.AllowlistAnnotation], " ")).
+			Expect(r.Annotations).Should(HaveKey(allowlistAnnotation))
+			Expect(strings.Split(r.Annotations[allowlistAnnotation], " ")).
 				Should(ConsistOf("10.200.15.13", "10.200.15.132", "192.168.10.32"))
 
 		})
@@ -298,7 +306,7 @@ var _ = Describe("controller", Ordered, func() {
 			Expect(r).NotTo(BeNil())
 
 			r.Labels[controller.IPShieldWatchedResourceLabel] = strconv.FormatBool(true)
-			r.Annotations[controller.AllowlistAnnotation] = "10.200.15.13"
+			r.Annotations[allowlistAnnotation] = "10.200.15.13"
 
 			Expect(client.Update(context.TODO(), r)).NotTo(HaveOccurred())
 
@@ -315,8 +323,8 @@ var _ = Describe("controller", Ordered, func() {
 				Should(Succeed())
 			Expect(r).NotTo(BeNil())
 
-			Expect(r.Annotations).Should(HaveKey(controller.AllowlistAnnotation))
-			Expect(strings.Split(r.Annotations[controller.AllowlistAnnotation], " ")).
+			Expect(r.Annotations).Should(HaveKey(allowlistAnnotation))
+			Expect(strings.Split(r.Annotations[allowlistAnnotation], " ")).
 				Should(ConsistOf("10.200.15.13", "10.200.15.132"))
 
 			Expect(client.Delete(context.TODO(), allowlist)).Error().ShouldNot(HaveOccurred())
@@ -327,8 +335,8 @@ var _ = Describe("controller", Ordered, func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(r).NotTo(BeNil())
 
-			Expect(r.Annotations).Should(HaveKey(controller.AllowlistAnnotation))
-			Expect(strings.Split(r.Annotations[controller.AllowlistAnnotation], " ")).
+			Expect(r.Annotations).Should(HaveKey(allowlistAnnotation))
+			Expect(strings.Split(r.Annotations[allowlistAnnotation], " ")).
 				Should(ConsistOf("10.200.15.13"))
 		})
 
@@ -340,7 +348,7 @@ var _ = Describe("controller", Ordered, func() {
 			Expect(r).NotTo(BeNil())
 
 			r.Labels[controller.IPShieldWatchedResourceLabel] = strconv.FormatBool(true)
-			r.Annotations[controller.AllowlistAnnotation] = "10.200.15.13"
+			r.Annotations[allowlistAnnotation] = "10.200.15.13"
 
 			Expect(client.Update(context.TODO(), r)).NotTo(HaveOccurred())
 
@@ -356,8 +364,8 @@ var _ = Describe("controller", Ordered, func() {
 			Expect(client.Get(context.TODO(), types.NamespacedName{Name: RouteName, Namespace: TestingNamespace}, r))
 			Expect(r).NotTo(BeNil())
 
-			Expect(r.Annotations).Should(HaveKey(controller.AllowlistAnnotation))
-			Expect(strings.Split(r.Annotations[controller.AllowlistAnnotation], " ")).
+			Expect(r.Annotations).Should(HaveKey(allowlistAnnotation))
+			Expect(strings.Split(r.Annotations[allowlistAnnotation], " ")).
 				Should(ConsistOf("10.200.15.13", "10.200.15.132"))
 
 			r.Labels[controller.IPShieldWatchedResourceLabel] = strconv.FormatBool(false)
@@ -369,8 +377,8 @@ var _ = Describe("controller", Ordered, func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(r).NotTo(BeNil())
 
-			Expect(r.Annotations).Should(HaveKey(controller.AllowlistAnnotation))
-			Expect(strings.Split(r.Annotations[controller.AllowlistAnnotation], " ")).
+			Expect(r.Annotations).Should(HaveKey(allowlistAnnotation))
+			Expect(strings.Split(r.Annotations[allowlistAnnotation], " ")).
 				Should(ConsistOf("10.200.15.13"))
 		})
 
@@ -397,8 +405,8 @@ var _ = Describe("controller", Ordered, func() {
 			Expect(client.Get(context.TODO(), types.NamespacedName{Name: RouteName, Namespace: TestingNamespace}, r))
 			Expect(r).NotTo(BeNil())
 
-			Expect(r.Annotations).Should(HaveKey(controller.AllowlistAnnotation))
-			Expect(strings.Split(r.Annotations[controller.AllowlistAnnotation], " ")).
+			Expect(r.Annotations).Should(HaveKey(allowlistAnnotation))
+			Expect(strings.Split(r.Annotations[allowlistAnnotation], " ")).
 				Should(ConsistOf("10.200.15.13", "10.200.15.132"))
 
 			r.Labels[controller.IPShieldWatchedResourceLabel] = strconv.FormatBool(false)
@@ -410,7 +418,7 @@ var _ = Describe("controller", Ordered, func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(r).NotTo(BeNil())
 
-			Expect(r.Annotations).ShouldNot(HaveKey(controller.AllowlistAnnotation))
+			Expect(r.Annotations).ShouldNot(HaveKey(allowlistAnnotation))
 		})
 
 		It("allowlist annotation was modified directly", func() {
@@ -436,11 +444,11 @@ var _ = Describe("controller", Ordered, func() {
 				To(Succeed())
 			Expect(r).NotTo(BeNil())
 
-			Expect(r.Annotations).Should(HaveKey(controller.AllowlistAnnotation))
-			Expect(strings.Split(r.Annotations[controller.AllowlistAnnotation], " ")).
+			Expect(r.Annotations).Should(HaveKey(allowlistAnnotation))
+			Expect(strings.Split(r.Annotations[allowlistAnnotation], " ")).
 				Should(ConsistOf("10.200.15.13", "10.200.15.132"))
 
-			r.Annotations[controller.AllowlistAnnotation] = "10.13.42.54"
+			r.Annotations[allowlistAnnotation] = "10.13.42.54"
 			Expect(client.Update(context.TODO(), r)).Error().ShouldNot(HaveOccurred())
 
 			time.Sleep(5 * time.Second)
@@ -449,9 +457,10 @@ var _ = Describe("controller", Ordered, func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(r).NotTo(BeNil())
 
-			Expect(r.Annotations).Should(HaveKey(controller.AllowlistAnnotation))
-			Expect(strings.Split(r.Annotations[controller.AllowlistAnnotation], " ")).
-				Should(ConsistOf("10.200.15.13", "10.200.15.132", "10.13.42.54"))
+			// The default Enforce drift policy reverts ranges modified outside of IPShield
+			Expect(r.Annotations).Should(HaveKey(allowlistAnnotation))
+			Expect(strings.Split(r.Annotations[allowlistAnnotation], " ")).
+				Should(ConsistOf("10.200.15.13", "10.200.15.132"))
 		})
 
 		It("has multiple CRs", func() {
@@ -481,8 +490,8 @@ var _ = Describe("controller", Ordered, func() {
 				To(Succeed())
 			Expect(r).NotTo(BeNil())
 
-			Expect(r.Annotations).Should(HaveKey(controller.AllowlistAnnotation))
-			Expect(strings.Split(r.Annotations[controller.AllowlistAnnotation], " ")).
+			Expect(r.Annotations).Should(HaveKey(allowlistAnnotation))
+			Expect(strings.Split(r.Annotations[allowlistAnnotation], " ")).
 				Should(ConsistOf("10.200.15.13", "10.200.15.132", "10.200.15.14", "10.200.15.135"))
 
 			Expect(client.Delete(context.TODO(), allowlist)).Error().ShouldNot(HaveOccurred())